@@ -0,0 +1,130 @@
+// Command gobel is a tool for working with JavaScript source.
+//
+// Usage:
+//
+//	gobel tokens [-json] [-whitespace] [-module] [file]
+//
+// The tokens command prints the tokens of file, or of the standard input
+// when file is "-" or missing. Each token is printed with its type, value,
+// line, column and the goal it was lexed with, as an aligned table or with
+// -json as JSON Lines. The goal of each token is chosen from the tokens
+// before it as es6.Tokenize does.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/crhntr/gobel/es6"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command with args and returns its exit status
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: gobel <command> [arguments]\n\ncommands:\n  tokens  print the tokens of a file")
+		return 2
+	}
+	switch args[0] {
+	case "tokens":
+		return runTokens(args[1:], stdin, stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "gobel: unknown command %q\n", args[0])
+		return 2
+	}
+}
+
+// jsonToken is the JSON Lines form of a token
+type jsonToken struct {
+	Type   string `json:"type"`
+	Value  string `json:"value"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Goal   string `json:"goal"`
+}
+
+func runTokens(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("tokens", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: gobel tokens [-json] [-whitespace] [-module] [file]")
+		flags.PrintDefaults()
+	}
+	asJSON := flags.Bool("json", false, "print the tokens as JSON Lines")
+	whitespace := flags.Bool("whitespace", false, "include whitespace, line terminators and comments")
+	module := flags.Bool("module", false, "lex the input as module code instead of a script")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return 2
+	}
+
+	name, r := "<stdin>", stdin
+	if file := flags.Arg(0); file != "" && file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			fmt.Fprintf(stderr, "gobel: %s\n", err)
+			return 1
+		}
+		defer f.Close()
+		name, r = file, f
+	}
+
+	l := es6.NewLexer(name, r, *module)
+	l.CaptureWhitespaceTokens = *whitespace
+	tokens := es6.TokenizeLexer(l)
+
+	var err error
+	if *asJSON {
+		err = writeTokensJSON(stdout, tokens)
+	} else {
+		err = writeTokensTable(stdout, tokens)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "gobel: %s\n", err)
+		return 1
+	}
+	if diagnostics := l.Diagnostics(); len(diagnostics) > 0 {
+		for _, d := range diagnostics {
+			fmt.Fprintf(stderr, "gobel: %s\n", d)
+		}
+		return 1
+	}
+	return 0
+}
+
+func writeTokensJSON(w io.Writer, tokens []es6.Token) error {
+	enc := json.NewEncoder(w)
+	for _, tok := range tokens {
+		err := enc.Encode(jsonToken{
+			Type:   tok.Type.String(),
+			Value:  tok.Value,
+			Line:   tok.Line,
+			Column: tok.Column,
+			Goal:   tok.Goal.String(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTokensTable(w io.Writer, tokens []es6.Token) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tVALUE\tLINE\tCOLUMN\tGOAL")
+	for _, tok := range tokens {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\n", tok.Type, strconv.Quote(tok.Value), tok.Line, tok.Column, tok.Goal)
+	}
+	return tw.Flush()
+}
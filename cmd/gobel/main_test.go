@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunTokens_JSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"tokens", "-json"}, strings.NewReader("a = /re/g"), &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit status 0 but got %d: %s", code, stderr.String())
+	}
+	expected := []jsonToken{
+		{Type: "IdentifierName", Value: "a", Line: 1, Column: 0, Goal: "InputElementRegExp"},
+		{Type: "Punctuator", Value: "=", Line: 1, Column: 2, Goal: "InputElementDiv"},
+		{Type: "RegEx", Value: "/re/g", Line: 1, Column: 4, Goal: "InputElementRegExp"},
+		{Type: "EOF", Value: "", Line: 1, Column: 9, Goal: "InputElementDiv"},
+	}
+	scanner := bufio.NewScanner(&stdout)
+	for i := 0; scanner.Scan(); i++ {
+		var tok jsonToken
+		if err := json.Unmarshal(scanner.Bytes(), &tok); err != nil {
+			t.Fatal(err)
+		}
+		if i >= len(expected) || tok != expected[i] {
+			t.Errorf("%d: unexpected token %+v", i, tok)
+		}
+	}
+}
+
+func TestRunTokens_Table(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"tokens", "-whitespace"}, strings.NewReader("<!-- x"), &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit status 0 but got %d: %s", code, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 || strings.Fields(lines[0])[0] != "TYPE" || strings.Fields(lines[1])[0] != "SingleLineComment" {
+		t.Errorf("expected a header and the HTML-like comment but got\n%s", stdout.String())
+	}
+	if !strings.HasPrefix(lines[1], "SingleLineComment  \" x\"") {
+		t.Errorf("expected the columns to be aligned but got\n%s", stdout.String())
+	}
+}
+
+func TestRunTokens_Module(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"tokens", "-module", "-"}, strings.NewReader("<!-- x"), &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit status 0 but got %d: %s", code, stderr.String())
+	}
+	if lines := strings.Split(stdout.String(), "\n"); !strings.HasPrefix(lines[1], "Punctuator") {
+		t.Errorf("expected <!-- not to be a comment in a module but got\n%s", stdout.String())
+	}
+}
+
+func TestRunTokens_Diagnostics(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"tokens"}, strings.NewReader("'abc"), &stdout, &stderr); code != 1 {
+		t.Errorf("expected exit status 1 but got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "did not reach end of string literal") {
+		t.Errorf("expected the diagnostic to be reported but got %q", stderr.String())
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"nope"}, nil, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit status 2 but got %d", code)
+	}
+	if code := run([]string{"tokens", "a.js", "b.js"}, nil, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit status 2 but got %d", code)
+	}
+}
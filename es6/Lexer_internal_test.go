@@ -3,6 +3,8 @@ package es6
 import (
 	"fmt"
 	"io/ioutil"
+	"math"
+	"strings"
 	"testing"
 )
 
@@ -45,19 +47,30 @@ func TestLex_Terminator_And_Whitespace(t *testing.T) {
 	expectedTokens(t, expected, l)
 }
 
+// reservedWords returns the words that are lexed as ReservedWords in strict
+// or sloppy mode code
+func reservedWords(strict bool) []string {
+	words := append([]string{}, currentReservedWords...)
+	words = append(words, futureReservedWords...)
+	words = append(words, literals...)
+	if strict {
+		words = append(words, futureResdervedWordsStrict...)
+	}
+	return words
+}
+
 func TestLex_ReservedWord1(t *testing.T) {
 	expected := []Token{}
 	js := ""
 	ws := " "
 
-	lJs := Lexer{}
-	lJs.setStrict()
-	for _, word := range lJs.reservedWords {
+	words := reservedWords(true)
+	for _, word := range words {
 		js += word + ws
 	}
 	l := Lex("", js, true)
 	l.CaptureWhitespaceTokens = true
-	for _, word := range l.reservedWords {
+	for _, word := range words {
 		expected = append(expected, Token{Type: ReservedWordToken, Value: word})
 		expected = append(expected, Token{Type: WhiteSpaceToken, Value: ws})
 	}
@@ -70,15 +83,14 @@ func TestLex_ReservedWord2(t *testing.T) {
 	js := ""
 	ws := " "
 
-	lJs := Lexer{}
-	lJs.unsetStrict()
-	for _, word := range lJs.reservedWords {
+	words := reservedWords(false)
+	for _, word := range words {
 		js += word + ws
 	}
 
 	l := Lex("", js, false)
 	l.CaptureWhitespaceTokens = true
-	for _, word := range l.reservedWords {
+	for _, word := range words {
 		expected = append(expected, Token{Type: ReservedWordToken, Value: word})
 		expected = append(expected, Token{Type: WhiteSpaceToken, Value: ws})
 	}
@@ -86,6 +98,75 @@ func TestLex_ReservedWord2(t *testing.T) {
 	expectedTokens(t, expected, l)
 }
 
+func TestLex_ReservedWordBoundary(t *testing.T) {
+	expected := []Token{
+		Token{Type: IdentifierNameToken, Value: "format"},
+		Token{Type: IdentifierNameToken, Value: "newValue"},
+		Token{Type: IdentifierNameToken, Value: "docs"},
+		Token{Type: IdentifierNameToken, Value: "inner"},
+		Token{Type: IdentifierNameToken, Value: "nullable"},
+		Token{Type: ReservedWordToken, Value: "in"},
+		Token{Type: ReservedWordToken, Value: "new"},
+		Token{Type: ReservedWordToken, Value: "enum"},
+	}
+	js := "format newValue docs inner nullable in new enum"
+	l := Lex("", js, true)
+	expectedTokens(t, expected, l)
+}
+
+func TestLex_ReservedWordStrict(t *testing.T) {
+	for _, row := range []struct {
+		strict bool
+		typ    TokenType
+	}{
+		{true, ReservedWordToken},
+		{false, IdentifierNameToken},
+	} {
+		l := Lex("", "implements", row.strict)
+		if tok := l.Next(InputElementDiv); tok.Type != row.typ {
+			t.Errorf("strict %t: expected %s but got %s", row.strict, row.typ, tok)
+		}
+	}
+}
+
+func TestLex_ContextualWord(t *testing.T) {
+	js := "let static yield await of get set as from target letter"
+	l := Lex("", js, true)
+	for i, word := range strings.Fields(js) {
+		tok := l.Next(InputElementDiv)
+		if tok.Type != IdentifierNameToken || tok.Value != word {
+			t.Errorf("expected IdentifierName %q but got %s", word, tok)
+		}
+		if contextual := i < len(contextualWords); tok.Contextual != contextual {
+			t.Errorf("%s: expected Contextual to be %t", tok, contextual)
+		}
+	}
+	l = Lex("", `l\u0065t`, true)
+	if tok := l.Next(InputElementDiv); tok.Contextual {
+		t.Errorf("expected an escaped name not to be contextual %s", tok)
+	}
+}
+
+func TestKeywordKindOf(t *testing.T) {
+	for _, row := range []struct {
+		kind  keywordKind
+		words []string
+	}{
+		{reservedKeyword, currentReservedWords},
+		{reservedKeyword, futureReservedWords},
+		{reservedKeyword, literals},
+		{strictReservedKeyword, futureResdervedWordsStrict},
+		{contextualKeyword, contextualWords},
+		{notKeyword, []string{"", "i", "fi", "iff", "Let", "instanceofs", "functio", "identifier"}},
+	} {
+		for _, word := range row.words {
+			if kind := keywordKindOf(word); kind != row.kind {
+				t.Errorf("%q: expected kind %d but got %d", word, row.kind, kind)
+			}
+		}
+	}
+}
+
 // func TestLex_EscapeSequence0(t *testing.T) {
 // 	expected := []Token{
 // 		Token{Type: IdentifierName, Value: "X"},
@@ -197,7 +278,8 @@ func TestLexJS(t *testing.T) {
 		Token{Type: IdentifierNameToken, Value: "fibonacci"},
 		Token{Type: PunctuatorToken, Value: "("},
 		Token{Type: IdentifierNameToken, Value: "n"},
-		Token{Type: NumericLiteralToken, Value: "-1"},
+		Token{Type: PunctuatorToken, Value: "-"},
+		Token{Type: NumericLiteralToken, Value: "1"},
 		Token{Type: PunctuatorToken, Value: ")"},
 		Token{Type: WhiteSpaceToken, Value: " "},
 		Token{Type: PunctuatorToken, Value: "+"},
@@ -205,7 +287,8 @@ func TestLexJS(t *testing.T) {
 		Token{Type: IdentifierNameToken, Value: "fibonacci"},
 		Token{Type: PunctuatorToken, Value: "("},
 		Token{Type: IdentifierNameToken, Value: "n"},
-		Token{Type: NumericLiteralToken, Value: "-2"},
+		Token{Type: PunctuatorToken, Value: "-"},
+		Token{Type: NumericLiteralToken, Value: "2"},
 		Token{Type: PunctuatorToken, Value: ")"},
 		Token{Type: LineTerminatorToken, Value: "\n"},
 		Token{Type: WhiteSpaceToken, Value: "  "},
@@ -263,13 +346,15 @@ func TestLexJS2(t *testing.T) {
 		Token{Type: IdentifierNameToken, Value: "fibonacci"},
 		Token{Type: PunctuatorToken, Value: "("},
 		Token{Type: IdentifierNameToken, Value: "n"},
-		Token{Type: NumericLiteralToken, Value: "-1"},
+		Token{Type: PunctuatorToken, Value: "-"},
+		Token{Type: NumericLiteralToken, Value: "1"},
 		Token{Type: PunctuatorToken, Value: ")"},
 		Token{Type: PunctuatorToken, Value: "+"},
 		Token{Type: IdentifierNameToken, Value: "fibonacci"},
 		Token{Type: PunctuatorToken, Value: "("},
 		Token{Type: IdentifierNameToken, Value: "n"},
-		Token{Type: NumericLiteralToken, Value: "-2"},
+		Token{Type: PunctuatorToken, Value: "-"},
+		Token{Type: NumericLiteralToken, Value: "2"},
 		Token{Type: PunctuatorToken, Value: ")"},
 		Token{Type: RightBracePunctuatorToken, Value: "}"},
 		Token{Type: ReservedWordToken, Value: "return"},
@@ -456,6 +541,7 @@ func TestLex_SingleLineComment2(t *testing.T) {
 func TestLex_Comments(t *testing.T) {
 	expected := []Token{
 		Token{Type: SingleLineCommentToken, Value: " Hello World!"},
+		Token{Type: LineTerminatorToken, Value: "\n"},
 		Token{Type: MultiLineCommentToken, Value: "This is a multi\nline comment "},
 	}
 	js := "// Hello World!\n/*This is a multi\nline comment */"
@@ -465,16 +551,134 @@ func TestLex_Comments(t *testing.T) {
 }
 
 // Test EscapeSequence
+func TestLex_HashbangComment(t *testing.T) {
+	for _, module := range []bool{false, true} {
+		l := Lex("", "#!/usr/bin/env node\nfoo", true)
+		l.Module = module
+		l.CaptureWhitespaceTokens = true
+		expectedTokens(t, []Token{
+			Token{Type: HashbangCommentToken, Value: "/usr/bin/env node"},
+			Token{Type: LineTerminatorToken, Value: "\n"},
+			Token{Type: IdentifierNameToken, Value: "foo"},
+		}, l)
+	}
+
+	l := Lex("", " #!/usr/bin/env node", false)
+	l.CaptureWhitespaceTokens = true
+	expectedTokens(t, []Token{
+		Token{Type: WhiteSpaceToken, Value: " "},
+		Token{Type: ErrorToken, Value: "unexpected character '#'"},
+	}, l)
+}
+
+func TestLex_HTMLLikeComment(t *testing.T) {
+	js := "<!-- open\nx = 1 --> y\n  --> close\n/*\n*/ --> after"
+	expectedTokens(t, []Token{
+		Token{Type: IdentifierNameToken, Value: "x"},
+		Token{Type: PunctuatorToken, Value: "="},
+		Token{Type: NumericLiteralToken, Value: "1"},
+		Token{Type: PunctuatorToken, Value: "--"},
+		Token{Type: PunctuatorToken, Value: ">"},
+		Token{Type: IdentifierNameToken, Value: "y"},
+		Token{Type: EOFToken},
+	}, Lex("", js, false))
+
+	l := Lex("", js, false)
+	l.CaptureWhitespaceTokens = true
+	var comments []string
+	for tok := l.Next(InputElementDiv); tok.Type != EOFToken; tok = l.Next(InputElementDiv) {
+		if tok.Type == SingleLineCommentToken {
+			comments = append(comments, tok.Value)
+		}
+	}
+	if fmt.Sprint(comments) != "[ open  close  after]" {
+		t.Errorf("expected the HTML-like comments to be lexed but got %q", comments)
+	}
+}
+
+func TestLex_HTMLLikeCommentStrict(t *testing.T) {
+	expected := []Token{
+		Token{Type: IdentifierNameToken, Value: "x"},
+		Token{Type: PunctuatorToken, Value: "<"},
+		Token{Type: PunctuatorToken, Value: "!"},
+		Token{Type: PunctuatorToken, Value: "--"},
+		Token{Type: IdentifierNameToken, Value: "y"},
+		Token{Type: PunctuatorToken, Value: "--"},
+		Token{Type: PunctuatorToken, Value: ">"},
+	}
+	js := "x<!--y\n-->"
+	expectedTokens(t, expected, Lex("", js, true))
+
+	module := Lex("", js, false)
+	module.Module = true
+	expectedTokens(t, expected, module)
+}
+
 func TestLex_lexEscapeSequence01(t *testing.T) {
 	expected := []Token{
-		Token{Type: StringLiteralToken, Value: "\"\\u0074\\x61z\nzz\""},
+		Token{Type: StringLiteralToken, Value: "\"\\u0074\\x61z\\\nzz\""},
 	}
-	js := "\"\\u0074\\x61z\nzz\""
+	js := "\"\\u0074\\x61z\\\nzz\""
 	l := Lex("", js, true)
 	l.CaptureWhitespaceTokens = true
 	expectedTokens(t, expected, l)
 }
 
+func TestLex_StringLiteralCooked(t *testing.T) {
+	for _, row := range []struct {
+		js, cooked string
+	}{
+		{`"a\"b"`, `a"b`},
+		{`'a\'b'`, `a'b`},
+		{`"\\"`, `\`},
+		{`"\b\f\n\r\t\v"`, "\b\f\n\r\t\v"},
+		{`"\0"`, "\x00"},
+		{`"\x41\x7a"`, "Az"},
+		{`"\u0041\u00e9"`, "A\u00e9"},
+		{`"\u{1F600}"`, "\U0001F600"},
+		{`"\uD83D\uDE00"`, "\U0001F600"},
+		{"\"a\\\nb\"", "ab"},
+		{"\"a\\\r\nb\"", "ab"},
+		{"\"a\\\u2028b\"", "ab"},
+		{`"\q"`, "q"},
+		{`"\101"`, "A"},
+		{`"\08"`, "\x008"},
+		{`"\400"`, " 0"},
+		{`"\9"`, "9"},
+	} {
+		l := Lex("", row.js, false)
+		tok := l.Next(InputElementDiv)
+		if tok.Type != StringLiteralToken || tok.Value != row.js {
+			t.Errorf("expected a StringLiteral %s but got %s", row.js, tok)
+			continue
+		}
+		if tok.Cooked != row.cooked {
+			t.Errorf("%s: expected cooked value %q but got %q", row.js, row.cooked, tok.Cooked)
+		}
+	}
+}
+
+func TestLex_StringLiteralInvalidEscape(t *testing.T) {
+	for _, row := range []struct {
+		js, err string
+		strict  bool
+	}{
+		{`"\x4"`, "invalid hexadecimal escape sequence", false},
+		{`"\u12"`, "invalid unicode escape sequence", false},
+		{`"\u{}"`, "invalid unicode escape sequence", false},
+		{`"\u{110000}"`, "unicode escape sequence is out of range", false},
+		{`"\101"`, "octal escape sequences are not allowed in strict mode", true},
+		{`"\08"`, "octal escape sequences are not allowed in strict mode", true},
+		{`"\8"`, "\\8 is not allowed in strict mode", true},
+	} {
+		l := Lex("", row.js, row.strict)
+		expectedTokens(t, []Token{
+			Token{Type: ErrorToken, Value: row.err},
+			Token{Type: StringLiteralToken, Value: row.js},
+		}, l)
+	}
+}
+
 //
 // Test Identifier
 //
@@ -505,6 +709,58 @@ func TestLex_Identifier2(t *testing.T) {
 	expectedTokens(t, expected, l)
 }
 
+func TestLex_IdentifierUnicode(t *testing.T) {
+	for _, row := range []struct {
+		js, cooked string
+	}{
+		{"café", "café"},
+		{"π", "π"},
+		{"日本語", "日本語"},
+		{"\u2118x", "\u2118x"},   // Other_ID_Start
+		{"a\u00B7b", "a\u00B7b"}, // Other_ID_Continue
+		{"a\u200Cb", "a\u200Cb"}, // ZWNJ
+		{"a\u0301", "a\u0301"},   // Mn
+		{`\u0061bc`, "abc"},
+		{`a\u{62}c`, "abc"},
+		{`\u{1D4D0}`, "\U0001D4D0"},
+		{`\u0076ar`, "var"},
+	} {
+		l := Lex("", row.js, true)
+		tok := l.Next(InputElementDiv)
+		if tok.Type != IdentifierNameToken || tok.Value != row.js {
+			t.Errorf("expected an IdentifierName %s but got %s", row.js, tok)
+			continue
+		}
+		if tok.Cooked != row.cooked {
+			t.Errorf("%s: expected name %q but got %q", row.js, row.cooked, tok.Cooked)
+		}
+		if tok := l.Next(InputElementDiv); tok.Type != EOFToken {
+			t.Errorf("%s: expected EOF but got %s", row.js, tok)
+		}
+	}
+}
+
+func TestLex_IdentifierPatternSyntax(t *testing.T) {
+	// U+2E2F VERTICAL TILDE is a letter but it is also Pattern_Syntax
+	l := Lex("", "a\u2E2F", true)
+	expected := Token{Type: IdentifierNameToken, Value: "a"}
+	if tok := l.Next(InputElementDiv); !tok.Equals(expected) {
+		t.Errorf("expected %s but got %s", expected, tok)
+	}
+	if tok := l.Next(InputElementDiv); tok.Type != ErrorToken {
+		t.Errorf("expected an error but got %s", tok)
+	}
+}
+
+func TestLex_IdentifierInvalidEscape(t *testing.T) {
+	expected := []Token{
+		Token{Type: ErrorToken, Value: "escaped character U+0030 is not allowed in an identifier"},
+		Token{Type: IdentifierNameToken, Value: `\u0030a`},
+	}
+	l := Lex("", `\u0030a`, true)
+	expectedTokens(t, expected, l)
+}
+
 //
 // Test LineTerminator
 //
@@ -609,7 +865,8 @@ func TestLex_NumericLiteral5(t *testing.T) {
 
 func TestLex_NumericLiteral6(t *testing.T) {
 	expected := []Token{
-		Token{Type: NumericLiteralToken, Value: "-6"},
+		Token{Type: PunctuatorToken, Value: "-"},
+		Token{Type: NumericLiteralToken, Value: "6"},
 	}
 	js := "-6"
 	l := Lex("", js, true)
@@ -657,6 +914,141 @@ func TestLex_NumericLiteral10(t *testing.T) {
 	expectedTokens(t, expected, l)
 }
 
+func TestLex_NumericLiteralValue(t *testing.T) {
+	for _, row := range []struct {
+		js     string
+		number float64
+	}{
+		{"0", 0},
+		{"42", 42},
+		{".5", 0.5},
+		{"5.", 5},
+		{"1.25e2", 125},
+		{"1E-2", 0.01},
+		{"2e+3", 2000},
+		{"0xff", 255},
+		{"0XFF", 255},
+		{"0o17", 15},
+		{"0b101", 5},
+		{"0x20000000000001", 9007199254740992},
+		{"9007199254740993", 9007199254740992},
+		{"1e400", math.Inf(1)},
+		{"1e-400", 0},
+		{"017", 15},
+		{"08", 8},
+		{"019.5", 19.5},
+	} {
+		l := Lex("", row.js, false)
+		tok := l.Next(InputElementDiv)
+		if tok.Type != NumericLiteralToken || tok.Value != row.js {
+			t.Errorf("expected a NumericLiteral %s but got %s", row.js, tok)
+			continue
+		}
+		if tok.Number != row.number {
+			t.Errorf("%s: expected value %v but got %v", row.js, row.number, tok.Number)
+		}
+	}
+}
+
+func TestLex_NumericLiteralInvalid(t *testing.T) {
+	for _, row := range []struct {
+		js, err string
+	}{
+		{"0x", "bad number syntax: \"0x\""},
+		{"0b2", "bad number syntax: \"0b2\""},
+		{"0o8", "bad number syntax: \"0o8\""},
+		{"1e", "bad number syntax: \"1e\""},
+		{"1e+", "bad number syntax: \"1e+\""},
+		{"3in", "bad number syntax: \"3in\""},
+		{"0b12", "bad number syntax: \"0b12\""},
+	} {
+		l := Lex("", row.js, false)
+		expectedTokens(t, []Token{
+			Token{Type: ErrorToken, Value: row.err},
+		}, l)
+	}
+}
+
+func TestLex_NumericLiteralLegacyStrict(t *testing.T) {
+	for _, row := range []struct {
+		js, err string
+	}{
+		{"017", "legacy octal literals are not allowed in strict mode"},
+		{"08", "decimal literals with a leading zero are not allowed in strict mode"},
+	} {
+		l := Lex("", row.js, true)
+		expectedTokens(t, []Token{
+			Token{Type: ErrorToken, Value: row.err},
+			Token{Type: NumericLiteralToken, Value: row.js},
+		}, l)
+	}
+}
+
+func TestLex_NumericLiteralSeparator(t *testing.T) {
+	for _, row := range []struct {
+		js     string
+		number float64
+	}{
+		{"1_000_000", 1000000},
+		{"1_0.2_5e1_0", 10.25e10},
+		{"0xF_F", 255},
+		{"0b1_0", 2},
+		{"0o1_7", 15},
+	} {
+		l := Lex("", row.js, true)
+		tok := l.Next(InputElementDiv)
+		if tok.Type != NumericLiteralToken || tok.Value != row.js || tok.Number != row.number {
+			t.Errorf("expected a NumericLiteral %s with value %v but got %s with value %v", row.js, row.number, tok, tok.Number)
+		}
+	}
+	for _, row := range []struct {
+		js, bad string
+	}{
+		{"1_", "1_"},
+		{"1__0", "1__0"},
+		{"1_.5", "1_"},
+		{"1._5", "1._5"},
+		{"1e_5", "1e_5"},
+		{"0_1", "0_1"},
+		{"08_1", "08_1"},
+		{"0x_F", "0x_F"},
+	} {
+		l := Lex("", row.js, false)
+		if tok := l.Next(InputElementDiv); tok.Type != ErrorToken || tok.Value != fmt.Sprintf("bad number syntax: %q", row.bad) {
+			t.Errorf("expected %q to be a bad number but got %s", row.js, tok)
+		}
+	}
+}
+
+func TestLex_NumericLiteralBigInt(t *testing.T) {
+	for _, row := range []struct {
+		js, value string
+	}{
+		{"0n", "0"},
+		{"10n", "10"},
+		{"1_000n", "1000"},
+		{"0x1Fn", "31"},
+		{"0o17n", "15"},
+		{"0b11n", "3"},
+		{"123456789012345678901234567890n", "123456789012345678901234567890"},
+	} {
+		l := Lex("", row.js, true)
+		tok := l.Next(InputElementDiv)
+		if tok.Type != NumericLiteralToken || tok.Value != row.js || tok.BigInt == nil || tok.BigInt.String() != row.value {
+			t.Errorf("expected a BigInt %s with value %s but got %s with value %v", row.js, row.value, tok, tok.BigInt)
+		}
+	}
+	for _, js := range []string{"1.5n", "1e3n", "08n", "017n", ".5n"} {
+		l := Lex("", js, false)
+		if tok := l.Next(InputElementDiv); tok.Type != ErrorToken || tok.Value != fmt.Sprintf("bad number syntax: %q", js) {
+			t.Errorf("expected %q to be a bad number but got %s", js, tok)
+		}
+	}
+	if tok := Lex("", "1", true).Next(InputElementDiv); tok.BigInt != nil {
+		t.Errorf("expected a Number not a BigInt but got %s", tok.BigInt)
+	}
+}
+
 //
 // Test Punctuator
 //
@@ -677,6 +1069,102 @@ func TestLex_Punctuator1(t *testing.T) {
 	expectedTokens(t, expected, l)
 }
 
+func TestLex_PunctuatorLongestMatch(t *testing.T) {
+	expected := []Token{
+		Token{Type: PunctuatorToken, Value: ">>>="},
+		Token{Type: PunctuatorToken, Value: ">>="},
+		Token{Type: PunctuatorToken, Value: ">="},
+		Token{Type: PunctuatorToken, Value: ">="},
+		Token{Type: PunctuatorToken, Value: "==="},
+		Token{Type: PunctuatorToken, Value: ">"},
+		Token{Type: PunctuatorToken, Value: "..."},
+		Token{Type: PunctuatorToken, Value: "."},
+		Token{Type: IdentifierNameToken, Value: "a"},
+	}
+	js := ">>>=>>=>=>====>....a"
+	l := Lex("", js, false)
+	expectedTokens(t, expected, l)
+}
+
+func TestLex_OptionalChainingLookahead(t *testing.T) {
+	expected := []Token{
+		Token{Type: IdentifierNameToken, Value: "a"},
+		Token{Type: PunctuatorToken, Value: "?"},
+		Token{Type: NumericLiteralToken, Value: ".5"},
+		Token{Type: PunctuatorToken, Value: ":"},
+		Token{Type: IdentifierNameToken, Value: "b"},
+		Token{Type: PunctuatorToken, Value: "?."},
+		Token{Type: IdentifierNameToken, Value: "c"},
+	}
+	js := "a?.5:b?.c"
+	l := Lex("", js, true)
+	expectedTokens(t, expected, l)
+}
+
+func TestLex_PrivateName(t *testing.T) {
+	for _, row := range []struct {
+		js, name string
+	}{
+		{"#foo", "#foo"},
+		{"#\\u0061b", "#ab"},
+		{"#if", "#if"},
+	} {
+		l := Lex("", row.js, true)
+		tok := l.Next(InputElementDiv)
+		if tok.Type != PrivateNameToken || tok.Value != row.js || tok.Cooked != row.name {
+			t.Errorf("expected PrivateName %s named %q but got %s named %q", row.js, row.name, tok, tok.Cooked)
+		}
+	}
+	l := Lex("", "# foo", true)
+	if tok := l.Next(InputElementDiv); tok.Type != ErrorToken {
+		t.Errorf("expected an error for a # without a name but got %s", tok)
+	}
+}
+
+func TestLex_Edition(t *testing.T) {
+	for _, row := range []struct {
+		js, err string
+		typ     TokenType
+	}{
+		{"**", "\"**\" requires ES2016", PunctuatorToken},
+		{"**=", "\"**=\" requires ES2016", PunctuatorToken},
+		{"?.", "\"?.\" requires ES2020", PunctuatorToken},
+		{"??", "\"??\" requires ES2020", PunctuatorToken},
+		{"??=", "\"??=\" requires ES2021", PunctuatorToken},
+		{"||=", "\"||=\" requires ES2021", PunctuatorToken},
+		{"&&=", "\"&&=\" requires ES2021", PunctuatorToken},
+		{"1_000", "numeric separators requires ES2021", NumericLiteralToken},
+		{"10n", "BigInt literals requires ES2020", NumericLiteralToken},
+		{"#x", "private names requires ES2022", PrivateNameToken},
+	} {
+		l := Lex("", row.js, true)
+		l.Edition = ES2015
+		expectedTokens(t, []Token{
+			Token{Type: ErrorToken, Value: row.err},
+			Token{Type: row.typ, Value: row.js},
+		}, l)
+		if d := l.Diagnostics(); len(d) != 1 || d[0].Code != UnsupportedSyntax {
+			t.Errorf("%s: expected an UnsupportedSyntax diagnostic but got %v", row.js, d)
+		}
+
+		for _, edition := range []Edition{0, LatestEdition} {
+			l = Lex("", row.js, true)
+			l.Edition = edition
+			expectedTokens(t, []Token{
+				Token{Type: row.typ, Value: row.js},
+			}, l)
+		}
+	}
+
+	l := Lex("", "a ** b", true)
+	l.Edition = ES2016
+	l.Next(InputElementDiv)
+	l.Next(InputElementDiv)
+	if d := l.Diagnostics(); len(d) != 0 {
+		t.Errorf("expected ** to be allowed in ES2016 but got %v", d)
+	}
+}
+
 func TestLex_DivPunctuator1(t *testing.T) {
 	expected := []Token{
 		Token{Type: IdentifierNameToken, Value: "i"},
@@ -736,6 +1224,60 @@ func TestLex_RegEx01(t *testing.T) {
 	expectedTokensTable(t, expected, l)
 }
 
+func TestLex_RegExBody(t *testing.T) {
+	for _, row := range []struct {
+		js, pattern, flags string
+	}{
+		{`/abc/`, `abc`, ``},
+		{`/[/]/`, `[/]`, ``},
+		{`/a\/b/g`, `a\/b`, `g`},
+		{`/[\]/]+/gi`, `[\]/]+`, `gi`},
+		{`/\[/`, `\[`, ``},
+		{`/=/`, `=`, ``},
+		{`/([CGAT]{3}){1,}/gimuy`, `([CGAT]{3}){1,}`, `gimuy`},
+	} {
+		l := Lex("", row.js+";", true)
+		tok := l.Next(InputElementRegExp)
+		if tok.Type != RegExToken || tok.Value != row.js {
+			t.Errorf("expected a RegEx %s but got %s", row.js, tok)
+			continue
+		}
+		if tok.Pattern != row.pattern || tok.Flags != row.flags {
+			t.Errorf("%s: expected pattern %q and flags %q but got %q and %q", row.js, row.pattern, row.flags, tok.Pattern, tok.Flags)
+		}
+		if tok := l.Next(InputElementDiv); tok.Value != ";" {
+			t.Errorf("%s: expected ';' but got %s", row.js, tok)
+		}
+	}
+}
+
+func TestLex_RegExUnterminated(t *testing.T) {
+	for _, js := range []string{"/[/", "/a\\", "/a\n/", "/a\\\n/"} {
+		l := Lex("", js, true)
+		if tok := l.Next(InputElementRegExp); tok.Type != ErrorToken {
+			t.Errorf("%q: expected an error but got %s", js, tok)
+		}
+	}
+}
+
+func TestLex_RegExFlags(t *testing.T) {
+	expected := []Token{
+		Token{Type: ErrorToken, Value: "duplicate regular expression flag 'g'"},
+		Token{Type: ErrorToken, Value: "invalid regular expression flag 'x'"},
+		Token{Type: RegExToken, Value: "/a/gigx"},
+	}
+	l := Lex("", "/a/gigx", true)
+	for _, exp := range expected {
+		tok := l.Next(InputElementRegExp)
+		if !tok.Equals(exp) {
+			t.Errorf("expected %s but got %s", exp, tok)
+		}
+		if tok.Offset != 0 {
+			t.Errorf("expected %s to be reported at the start of the literal but got %d", tok, tok.Offset)
+		}
+	}
+}
+
 //
 // Test String
 //
@@ -806,6 +1348,37 @@ func TestLex_StringLiteralDoubleQuote2(t *testing.T) {
 	expectedTokens(t, expected, l)
 }
 
+func TestLex_StringLiteralLineTerminator(t *testing.T) {
+	for _, terminator := range []string{"\n", "\r"} {
+		expected := []Token{
+			Token{Type: IdentifierNameToken, Value: "a"},
+			Token{Type: PunctuatorToken, Value: "="},
+			Token{Type: ErrorToken, Value: "did not reach end of string literal before the end of the line"},
+			Token{Type: StringLiteralToken, Value: "'foo"},
+			Token{Type: LineTerminatorToken, Value: terminator},
+			Token{Type: IdentifierNameToken, Value: "b"},
+			Token{Type: PunctuatorToken, Value: "="},
+			Token{Type: StringLiteralToken, Value: "'bar'"},
+		}
+		l := Lex("", "a='foo"+terminator+"b='bar'", true)
+		l.CaptureWhitespaceTokens = true
+		expectedTokens(t, expected, l)
+		if len(l.Diagnostics()) != 1 || l.Diagnostics()[0].Code != UnterminatedStringLiteral {
+			t.Errorf("expected an UnterminatedStringLiteral diagnostic but got %v", l.Diagnostics())
+		}
+	}
+}
+
+func TestLex_StringLiteralLineSeparators(t *testing.T) {
+	l := Lex("", "'a\u2028b\u2029c'", true)
+	if tok := l.Next(InputElementDiv); tok.Type != StringLiteralToken || tok.Cooked != "a\u2028b\u2029c" {
+		t.Errorf("expected a string literal with the separators but got %s %q", tok.Type, tok.Cooked)
+	}
+	if len(l.Diagnostics()) != 0 {
+		t.Errorf("expected no diagnostics but got %v", l.Diagnostics())
+	}
+}
+
 //
 // Test Template literal
 //
@@ -959,6 +1532,76 @@ func TestLex_TemplateLiteral08(t *testing.T) {
 	expectedTokensTable(t, expected, l)
 }
 
+func TestLex_TemplateLiteralCooked(t *testing.T) {
+	for _, row := range []struct {
+		js, raw, cooked string
+		typ             TokenType
+	}{
+		{"`a\\`b`", "a\\`b", "a`b", NoSubstitutionTemplateToken},
+		{"`a\\${b`", "a\\${b", "a${b", NoSubstitutionTemplateToken},
+		{"`$a{b}$`", "$a{b}$", "$a{b}$", NoSubstitutionTemplateToken},
+		{"`\\n\\t\\x41\\u0042\\u{1F600}\\0`", "\\n\\t\\x41\\u0042\\u{1F600}\\0", "\n\tAB\U0001F600\x00", NoSubstitutionTemplateToken},
+		{"`a\r\nb\rc`", "a\nb\nc", "a\nb\nc", NoSubstitutionTemplateToken},
+		{"`a\\\r\nb`", "a\\\nb", "ab", NoSubstitutionTemplateToken},
+		{"`a ${", "a ", "a ", TemplateHeadToken},
+	} {
+		l := Lex("", row.js, true)
+		tok := l.Next(InputElementDiv)
+		if tok.Type != row.typ || tok.Value != row.js {
+			t.Errorf("expected a %s %q but got %s", row.typ, row.js, tok)
+			continue
+		}
+		if tok.Raw != row.raw || tok.Cooked != row.cooked || tok.CookedErr != nil {
+			t.Errorf("%q: expected raw %q and cooked %q but got %q and %q (%v)", row.js, row.raw, row.cooked, tok.Raw, tok.Cooked, tok.CookedErr)
+		}
+	}
+}
+
+func TestLex_TemplateLiteralInvalidEscape(t *testing.T) {
+	for _, row := range []struct {
+		js, err string
+	}{
+		{"`\\unicode`", "invalid unicode escape sequence"},
+		{"`\\xg`", "invalid hexadecimal escape sequence"},
+		{"`\\01`", "octal escape sequences are not allowed in template literals"},
+		{"`\\1`", "octal escape sequences are not allowed in template literals"},
+		{"`\\8`", "\\8 is not allowed in template literals"},
+	} {
+		l := Lex("", row.js, false)
+		tok := l.Next(InputElementDiv)
+		if tok.Type != NoSubstitutionTemplateToken || tok.Value != row.js {
+			t.Errorf("expected a NoSubstitutionTemplate %q but got %s", row.js, tok)
+			continue
+		}
+		if tok.CookedErr == nil || tok.CookedErr.Error() != row.err || tok.Cooked != "" {
+			t.Errorf("%q: expected an undefined cooked value because %q but got %q (%v)", row.js, row.err, tok.Cooked, tok.CookedErr)
+		}
+		if tok.Raw != row.js[1:len(row.js)-1] {
+			t.Errorf("%q: expected the raw value to be kept but got %q", row.js, tok.Raw)
+		}
+		if len(l.Diagnostics()) != 0 {
+			t.Errorf("%q: expected the parser to report the escape but got %v", row.js, l.Diagnostics())
+		}
+	}
+}
+
+func TestLex_TemplateLiteralNested(t *testing.T) {
+	expected := []TokenTest{
+		TokenTest{Token{Type: TemplateHeadToken, Value: "`a${"}, InputElementRegExp},
+		TokenTest{Token{Type: TemplateHeadToken, Value: "`b${"}, InputElementRegExp},
+		TokenTest{Token{Type: PunctuatorToken, Value: "{"}, InputElementRegExp},
+		TokenTest{Token{Type: RightBracePunctuatorToken, Value: "}"}, InputElementDiv},
+		TokenTest{Token{Type: TemplateMiddleToken, Value: "}c${"}, InputElementTemplateTail},
+		TokenTest{Token{Type: NoSubstitutionTemplateToken, Value: "`d`"}, InputElementRegExp},
+		TokenTest{Token{Type: TemplateTailToken, Value: "}e`"}, InputElementTemplateTail},
+		TokenTest{Token{Type: TemplateTailToken, Value: "}f`"}, InputElementTemplateTail},
+		TokenTest{Token{Type: EOFToken}, InputElementDiv},
+	}
+	js := "`a${`b${{}}c${`d`}e`}f`"
+	l := Lex("", js, true)
+	expectedTokensTable(t, expected, l)
+}
+
 //
 // Test WhiteSpaceToken
 //
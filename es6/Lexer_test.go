@@ -1,8 +1,13 @@
 package es6_test
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/crhntr/gobel/es6"
 )
@@ -170,3 +175,514 @@ func TestLexer_Peek(t *testing.T) {
 		}
 	}
 }
+
+func TestNewLexer(t *testing.T) {
+	testData, err := ioutil.ReadFile("testdata/TestLexJS3.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	js := strings.Repeat(string(testData), 64)
+
+	expected := es6.Lex("", js, true)
+	expected.CaptureWhitespaceTokens = true
+	l := es6.NewLexer("", iotest.OneByteReader(strings.NewReader(js)), true)
+	l.CaptureWhitespaceTokens = true
+
+	for i := 0; ; i++ {
+		want := expected.Next(es6.InputElementRegExp)
+		got := l.Next(es6.InputElementRegExp)
+		if !got.Equals(want) || got.Offset != want.Offset {
+			t.Fatalf("token %d: expected token: %s, but got %s", i, want, got)
+		}
+		if want.Type == es6.EOFToken || want.Type == es6.ErrorToken {
+			break
+		}
+	}
+	if err := l.Err(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNewLexer_TokenAcrossReads(t *testing.T) {
+	r := io.MultiReader(strings.NewReader("a = 0x123"), strings.NewReader("45 / /x/gim"), strings.NewReader("uy"))
+	l := es6.NewLexer("", r, true)
+	l.Next(es6.InputElementDiv)
+	l.Next(es6.InputElementDiv)
+	if tok := l.Next(es6.InputElementDiv); tok.Value != "0x12345" || tok.Number != 0x12345 {
+		t.Errorf("expected 0x12345 but got %s with value %v", tok, tok.Number)
+	}
+	l.Next(es6.InputElementDiv)
+	if tok := l.Next(es6.InputElementRegExp); tok.Value != "/x/gimuy" || tok.Flags != "gimuy" {
+		t.Errorf("expected /x/gimuy but got %s with flags %q", tok, tok.Flags)
+	}
+	if len(l.Diagnostics()) != 0 {
+		t.Errorf("expected no diagnostics but got %v", l.Diagnostics())
+	}
+}
+
+func TestNewLexer_LongToken(t *testing.T) {
+	body := strings.Repeat("x", 1<<20)
+	l := es6.NewLexer("", iotest.HalfReader(strings.NewReader("/*"+body+"*/ a")), true)
+	l.CaptureWhitespaceTokens = true
+	if tok := l.Next(es6.InputElementDiv); tok.Type != es6.MultiLineCommentToken || tok.Value != body {
+		t.Errorf("expected a comment of %d bytes but got %s of %d bytes", len(body), tok.Type, len(tok.Value))
+	}
+}
+
+func TestNewLexer_ReadError(t *testing.T) {
+	readErr := errors.New("disk on fire")
+	r := io.MultiReader(strings.NewReader("var foo"), iotest.ErrReader(readErr))
+	l := es6.NewLexer("", r, true)
+
+	{
+		next := l.Next(es6.InputElementDiv)
+		expected := es6.Token{Type: es6.ReservedWordToken, Value: "var"}
+		if !next.Equals(expected) {
+			t.Errorf("expected token: %s, but got %s", expected, next)
+		}
+	}
+	{
+		next := l.Next(es6.InputElementDiv)
+		expected := es6.Token{Type: es6.IdentifierNameToken, Value: "foo"}
+		if !next.Equals(expected) {
+			t.Errorf("expected token: %s, but got %s", expected, next)
+		}
+	}
+	{
+		next := l.Next(es6.InputElementDiv)
+		expected := es6.Token{Type: es6.ErrorToken, Value: "failed to read input: disk on fire"}
+		if !next.Equals(expected) {
+			t.Errorf("expected token: %s, but got %s", expected, next)
+		}
+	}
+	if err := l.Err(); err != readErr {
+		t.Errorf("expected Err to return %q, but got %v", readErr, err)
+	}
+}
+
+func TestLexer_NewlineBefore(t *testing.T) {
+	for _, row := range []struct {
+		js      string
+		newline bool
+	}{
+		{"a b", false},
+		{"a\nb", true},
+		{"a\r\nb", true},
+		{"a\u2028b", true},
+		{"a /* comment */ b", false},
+		{"a /* multi\nline */ b", true},
+		{"a // comment\nb", true},
+		{"a\n\n  b", true},
+	} {
+		for _, capture := range []bool{false, true} {
+			l := es6.Lex("", row.js, true)
+			l.CaptureWhitespaceTokens = capture
+
+			var a, b es6.Token
+			for tok := l.Next(es6.InputElementDiv); tok.Type != es6.EOFToken && tok.Type != es6.ErrorToken; tok = l.Next(es6.InputElementDiv) {
+				switch tok.Value {
+				case "a":
+					a = tok
+				case "b":
+					b = tok
+				}
+			}
+			if a.NewlineBefore {
+				t.Errorf("%q: the first token should not have a newline before it", row.js)
+			}
+			if b.NewlineBefore != row.newline {
+				t.Errorf("%q (capture whitespace: %t): expected NewlineBefore to be %t", row.js, capture, row.newline)
+			}
+		}
+	}
+}
+
+func TestLexer_NextSkipsLineTerminators(t *testing.T) {
+	l := es6.Lex("", "a\n\nb", true)
+	{
+		next := l.Next(es6.InputElementDiv)
+		expected := es6.Token{Type: es6.IdentifierNameToken, Value: "a"}
+		if !next.Equals(expected) {
+			t.Errorf("expected token: %s, but got %s", expected, next)
+		}
+	}
+	{
+		next := l.Next(es6.InputElementDiv)
+		expected := es6.Token{Type: es6.IdentifierNameToken, Value: "b"}
+		if !next.Equals(expected) {
+			t.Errorf("expected token: %s, but got %s", expected, next)
+		}
+	}
+}
+
+func TestLexer_LeadingComments(t *testing.T) {
+	js := "/* license */\n// about a\na = /*#__PURE__*/ b(); // about the call\n/** docs */ c"
+	l := es6.Lex("", js, true)
+
+	comments := func(tokens []es6.Token) []string {
+		values := []string{}
+		for _, tok := range tokens {
+			values = append(values, tok.Value)
+		}
+		return values
+	}
+	for _, expected := range []struct {
+		value             string
+		leading, trailing []string
+	}{
+		{"a", []string{" license ", " about a"}, []string{}},
+		{"=", []string{}, []string{}},
+		{"b", []string{"#__PURE__"}, []string{}},
+		{"(", []string{}, []string{}},
+		{")", []string{}, []string{}},
+		{";", []string{}, []string{" about the call"}},
+		{"c", []string{"* docs "}, []string{}},
+	} {
+		tok := l.Next(es6.InputElementDiv)
+		if tok.Value != expected.value {
+			t.Fatalf("expected %q but got %s", expected.value, tok)
+		}
+		if got := comments(tok.LeadingComments); fmt.Sprint(got) != fmt.Sprint(expected.leading) {
+			t.Errorf("%s: expected leading comments %q but got %q", tok, expected.leading, got)
+		}
+		if got := comments(l.TrailingComments()); fmt.Sprint(got) != fmt.Sprint(expected.trailing) {
+			t.Errorf("%s: expected trailing comments %q but got %q", tok, expected.trailing, got)
+		}
+	}
+}
+
+func TestLexer_CommentsBeforeEOF(t *testing.T) {
+	l := es6.Lex("", "a // trailing\n/* end */", true)
+	l.Next(es6.InputElementDiv)
+	if trailing := l.TrailingComments(); len(trailing) != 1 || trailing[0].Value != " trailing" {
+		t.Errorf("expected the trailing comment of a but got %v", trailing)
+	}
+	eof := l.Next(es6.InputElementDiv)
+	if eof.Type != es6.EOFToken {
+		t.Fatalf("expected EOF but got %s", eof)
+	}
+	if len(eof.LeadingComments) != 1 || eof.LeadingComments[0].Value != " end " {
+		t.Errorf("expected the last comment to lead EOF but got %v", eof.LeadingComments)
+	}
+}
+
+func TestLexer_Diagnostics(t *testing.T) {
+	js := "a # 1o b\n\"\\x4\" /* open"
+	l := es6.Lex("", js, true)
+
+	var types []string
+	for tok := l.Next(es6.InputElementDiv); tok.Type != es6.EOFToken; tok = l.Next(es6.InputElementDiv) {
+		types = append(types, tok.Type.String())
+	}
+	expectedTypes := "[IdentifierName Error Error IdentifierName Error StringLiteral Error]"
+	if fmt.Sprint(types) != expectedTypes {
+		t.Errorf("expected tokens %s but got %v", expectedTypes, types)
+	}
+
+	expected := []es6.Diagnostic{
+		{Code: es6.UnexpectedCharacter, Message: "unexpected character '#'", FilePosition: es6.FilePosition{Offset: 2}},
+		{Code: es6.InvalidNumericLiteral, Message: "bad number syntax: \"1o\"", FilePosition: es6.FilePosition{Offset: 4}},
+		{Code: es6.InvalidEscapeSequence, Message: "invalid hexadecimal escape sequence", FilePosition: es6.FilePosition{Offset: 9}},
+		{Code: es6.UnterminatedComment, Message: "no multi line comment terminator \"*/\"", FilePosition: es6.FilePosition{Offset: 15}},
+	}
+	diagnostics := l.Diagnostics()
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("expected %d diagnostics but got %d: %v", len(expected), len(diagnostics), diagnostics)
+	}
+	for i, d := range diagnostics {
+		if d.Code != expected[i].Code || d.Message != expected[i].Message || d.Offset != expected[i].Offset {
+			t.Errorf("expected diagnostic %s %q at %d but got %s %q at %d",
+				expected[i].Code, expected[i].Message, expected[i].Offset, d.Code, d.Message, d.Offset)
+		}
+	}
+}
+
+func TestLexer_DiagnosticPosition(t *testing.T) {
+	for _, tc := range []struct {
+		js      string
+		edition es6.Edition
+		strict  bool
+		offset  int
+	}{
+		{js: "a ?? b", edition: es6.ES2019, offset: 2},
+		{js: "a = 017", strict: true, offset: 4},
+		{js: "a\n  b = 0o9", offset: 8},
+	} {
+		l := es6.Lex("", tc.js, tc.strict)
+		l.Edition = tc.edition
+		for l.Next(es6.InputElementDiv).Type != es6.EOFToken {
+		}
+		d := l.Diagnostics()
+		if len(d) != 1 {
+			t.Errorf("%q: expected a diagnostic but got %v", tc.js, d)
+			continue
+		}
+		if d[0].Offset != tc.offset {
+			t.Errorf("%q: expected the diagnostic at the start of the token at %d but got %d", tc.js, tc.offset, d[0].Offset)
+		}
+	}
+}
+
+func TestLexer_Position(t *testing.T) {
+	js := "a = '😀' + b;\r\n  c\u2028d /* x\r\n */ e\n\"é\" f"
+	for _, row := range []struct {
+		utf16    bool
+		expected []es6.FilePosition
+	}{
+		{false, []es6.FilePosition{
+			{Offset: 0, Line: 1, Column: 0},
+			{Offset: 2, Line: 1, Column: 2},
+			{Offset: 4, Line: 1, Column: 4},
+			{Offset: 11, Line: 1, Column: 8},
+			{Offset: 13, Line: 1, Column: 10},
+			{Offset: 14, Line: 1, Column: 11},
+			{Offset: 19, Line: 2, Column: 2},
+			{Offset: 23, Line: 3, Column: 0},
+			{Offset: 35, Line: 4, Column: 4},
+			{Offset: 37, Line: 5, Column: 0},
+			{Offset: 42, Line: 5, Column: 4},
+		}},
+		{true, []es6.FilePosition{
+			{Offset: 0, Line: 1, Column: 0},
+			{Offset: 2, Line: 1, Column: 2},
+			{Offset: 4, Line: 1, Column: 4},
+			{Offset: 11, Line: 1, Column: 9},
+			{Offset: 13, Line: 1, Column: 11},
+			{Offset: 14, Line: 1, Column: 12},
+			{Offset: 19, Line: 2, Column: 2},
+			{Offset: 23, Line: 3, Column: 0},
+			{Offset: 35, Line: 4, Column: 4},
+			{Offset: 37, Line: 5, Column: 0},
+			{Offset: 42, Line: 5, Column: 4},
+		}},
+	} {
+		l := es6.Lex("", js, false)
+		l.UTF16Columns = row.utf16
+		for _, expected := range row.expected {
+			tok := l.Next(es6.InputElementDiv)
+			if got := tok.FilePosition; got != expected {
+				t.Errorf("utf16 %t: expected %s to be at offset %d line %d column %d but got offset %d line %d column %d",
+					row.utf16, tok, expected.Offset, expected.Line, expected.Column, got.Offset, got.Line, got.Column)
+			}
+		}
+		if tok := l.Next(es6.InputElementDiv); tok.Type != es6.EOFToken {
+			t.Errorf("expected EOF but got %s", tok)
+		}
+	}
+}
+
+func TestLexer_MarkReset(t *testing.T) {
+	js := "a b\n/x/ @ c"
+	for _, l := range []*es6.Lexer{
+		es6.Lex("", js, true),
+		es6.NewLexer("", iotest.OneByteReader(strings.NewReader(js)), true),
+	} {
+		l.Next(es6.InputElementDiv)
+		l.Peek(es6.InputElementDiv)
+		cp := l.Mark()
+
+		var first []es6.Token
+		for _, goal := range []es6.LexerGoal{es6.InputElementDiv, es6.InputElementRegExp, es6.InputElementDiv, es6.InputElementDiv} {
+			first = append(first, l.Next(goal))
+		}
+		if len(l.Diagnostics()) != 1 {
+			t.Errorf("expected a diagnostic for @ but got %v", l.Diagnostics())
+		}
+
+		l.Reset(cp)
+		if len(l.Diagnostics()) != 0 {
+			t.Errorf("expected Reset to forget diagnostics found after Mark but got %v", l.Diagnostics())
+		}
+		for i, goal := range []es6.LexerGoal{es6.InputElementDiv, es6.InputElementRegExp, es6.InputElementDiv, es6.InputElementDiv} {
+			tok := l.Next(goal)
+			if !tok.Equals(first[i]) || tok.FilePosition != first[i].FilePosition || tok.NewlineBefore != first[i].NewlineBefore {
+				t.Errorf("expected %s after Reset but got %s", first[i], tok)
+			}
+		}
+		l.Release(cp)
+		if tok := l.Next(es6.InputElementDiv); tok.Type != es6.EOFToken {
+			t.Errorf("expected EOF but got %s", tok)
+		}
+	}
+}
+
+func TestLexer_PeekN(t *testing.T) {
+	l := es6.Lex("", "let [a] = b", true)
+	for _, row := range []struct {
+		n     int
+		value string
+	}{
+		{2, "["}, {1, "let"}, {4, "]"}, {3, "a"}, {6, "b"},
+	} {
+		if tok := l.PeekN(row.n, es6.InputElementDiv); tok.Value != row.value {
+			t.Errorf("expected PeekN(%d) to return %q but got %s", row.n, row.value, tok)
+		}
+	}
+	if tok := l.PeekN(7, es6.InputElementDiv); tok.Type != es6.EOFToken {
+		t.Errorf("expected EOF but got %s", tok)
+	}
+	if tok := l.Next(es6.InputElementDiv); tok.Value != "let" {
+		t.Errorf("expected let but got %s", tok)
+	}
+}
+
+func TestLexer_PeekNGoal(t *testing.T) {
+	l := es6.Lex("", "/x/g;\n}a`", true)
+
+	expected := []struct {
+		n    int
+		goal es6.LexerGoal
+		tok  es6.Token
+	}{
+		{1, es6.InputElementDiv, es6.Token{Type: es6.DivPunctuatorToken, Value: "/"}},
+		{2, es6.InputElementDiv, es6.Token{Type: es6.IdentifierNameToken, Value: "x"}},
+		{1, es6.InputElementRegExp, es6.Token{Type: es6.RegExToken, Value: "/x/g"}},
+		{2, es6.InputElementRegExp, es6.Token{Type: es6.PunctuatorToken, Value: ";"}},
+		{3, es6.InputElementDiv, es6.Token{Type: es6.RightBracePunctuatorToken, Value: "}"}},
+		{3, es6.InputElementTemplateTail, es6.Token{Type: es6.TemplateTailToken, Value: "}a`"}},
+	}
+	for _, row := range expected {
+		tok := l.PeekN(row.n, row.goal)
+		if !tok.Equals(row.tok) || tok.Goal != row.goal {
+			t.Errorf("expected PeekN(%d, %s) to return %s but got %s lexed with %s", row.n, row.goal, row.tok, tok, tok.Goal)
+		}
+	}
+
+	for _, row := range []struct {
+		goal es6.LexerGoal
+		tok  es6.Token
+	}{
+		{es6.InputElementRegExp, es6.Token{Type: es6.RegExToken, Value: "/x/g"}},
+		{es6.InputElementDiv, es6.Token{Type: es6.PunctuatorToken, Value: ";"}},
+		{es6.InputElementDiv, es6.Token{Type: es6.RightBracePunctuatorToken, Value: "}"}},
+		{es6.InputElementDiv, es6.Token{Type: es6.IdentifierNameToken, Value: "a"}},
+	} {
+		if tok := l.Next(row.goal); !tok.Equals(row.tok) {
+			t.Errorf("expected Next(%s) to return %s but got %s", row.goal, row.tok, tok)
+		}
+	}
+	if len(l.Diagnostics()) != 0 {
+		t.Errorf("expected the tokens to be lexed again without diagnostics but got %v", l.Diagnostics())
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	for _, row := range []struct {
+		js       string
+		expected []string
+	}{
+		{"a = b / c /= d", []string{"IdentifierName", "Punctuator", "IdentifierName", "DivPunctuator", "IdentifierName", "DivPunctuator", "IdentifierName"}},
+		{"x = /=re/g.test(s)", []string{"IdentifierName", "Punctuator", "RegEx", "Punctuator", "IdentifierName", "Punctuator", "IdentifierName", "Punctuator"}},
+		{"if (x) /re/.test(y)", []string{"ReservedWord", "Punctuator", "IdentifierName", "Punctuator", "RegEx", "Punctuator", "IdentifierName", "Punctuator", "IdentifierName", "Punctuator"}},
+		{"f(x) / 2", []string{"IdentifierName", "Punctuator", "IdentifierName", "Punctuator", "DivPunctuator", "NumericLiteral"}},
+		{"a[0] / 2", []string{"IdentifierName", "Punctuator", "NumericLiteral", "Punctuator", "DivPunctuator", "NumericLiteral"}},
+		{"a++ / 2", []string{"IdentifierName", "Punctuator", "DivPunctuator", "NumericLiteral"}},
+		{"this / 2", []string{"ReservedWord", "DivPunctuator", "NumericLiteral"}},
+		{"return /x/", []string{"ReservedWord", "RegEx"}},
+		{"{} /x/", []string{"Punctuator", "RightBracePunctuator", "RegEx"}},
+		{"({} / 2)", []string{"Punctuator", "Punctuator", "RightBracePunctuator", "DivPunctuator", "NumericLiteral", "Punctuator"}},
+		{"`a${ {b: 1}.b / 2 }c${`d${/e/}`}f`", []string{
+			"TemplateHead", "Punctuator", "IdentifierName", "Punctuator", "NumericLiteral", "RightBracePunctuator",
+			"Punctuator", "IdentifierName", "DivPunctuator", "NumericLiteral", "TemplateMiddle",
+			"TemplateHead", "RegEx", "TemplateTail", "TemplateTail"}},
+	} {
+		var types []string
+		tokens := es6.Tokenize("", row.js)
+		for _, tok := range tokens {
+			switch tok.Type {
+			case es6.WhiteSpaceToken, es6.LineTerminatorToken, es6.EOFToken:
+			default:
+				types = append(types, tok.Type.String())
+			}
+		}
+		if fmt.Sprint(types) != fmt.Sprint(row.expected) {
+			t.Errorf("%s: expected %v but got %v", row.js, row.expected, types)
+		}
+		if last := tokens[len(tokens)-1]; last.Type != es6.EOFToken {
+			t.Errorf("%s: expected the tokens to end with EOF but got %s", row.js, last)
+		}
+	}
+}
+
+func TestTokenize_Trivia(t *testing.T) {
+	js := "a // b\n/* c */ d"
+	var src string
+	for _, tok := range es6.Tokenize("", js) {
+		switch tok.Type {
+		case es6.SingleLineCommentToken:
+			src += "//" + tok.Value
+		case es6.MultiLineCommentToken:
+			src += "/*" + tok.Value + "*/"
+		default:
+			src += tok.Value
+		}
+	}
+	if src != js {
+		t.Errorf("expected every token of %q but got %q", js, src)
+	}
+}
+
+// benchmarkSource repeats the scripts in testdata until they are about size
+// bytes long
+func benchmarkSource(b *testing.B, size int) string {
+	files, err := ioutil.ReadDir("testdata")
+	if err != nil {
+		b.Fatal(err)
+	}
+	var scripts []string
+	for _, file := range files {
+		buf, err := ioutil.ReadFile("testdata/" + file.Name())
+		if err != nil {
+			b.Fatal(err)
+		}
+		scripts = append(scripts, string(buf))
+	}
+	script := strings.Join(scripts, ";\n")
+	return strings.Repeat(script+";\n", size/len(script)+1)
+}
+
+func BenchmarkTokenize(b *testing.B) {
+	src := benchmarkSource(b, 1<<20)
+	b.SetBytes(int64(len(src)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		es6.Tokenize("", src)
+	}
+}
+
+func BenchmarkLex_Punctuators(b *testing.B) {
+	src := strings.Repeat("a >>>= b ?? c !== d ... e ** f => { g[h] = (i, j) } ; ", 1<<14)
+	benchmarkLex(b, src)
+}
+
+func BenchmarkLex_Keywords(b *testing.B) {
+	src := strings.Repeat("if while return typeof function instanceof interface yield let identifier ", 1<<14)
+	benchmarkLex(b, src)
+}
+
+func benchmarkLex(b *testing.B, src string) {
+	b.SetBytes(int64(len(src)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l := es6.Lex("", src, false)
+		for l.Next(es6.InputElementDiv).Type != es6.EOFToken {
+		}
+	}
+}
+
+func TestTokenize_Source(t *testing.T) {
+	for _, js := range []string{
+		"#!/usr/bin/env node\r\n/* a\n */ x = `a${ {b: 1} }c` // d\n<!-- e\n--> f\n",
+		"@ 09 0x 1_ /* unterminated",
+		"a = /re\nb", "'abc", "`abc${", "a = /re/zz;  b",
+		`Ab \u{zz} '\x'`,
+	} {
+		var src string
+		for _, tok := range es6.Tokenize("", js) {
+			src += tok.Source
+		}
+		if src != js {
+			t.Errorf("expected the Source of the tokens to be %q but got %q", js, src)
+		}
+	}
+}
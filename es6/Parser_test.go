@@ -1,7 +1,118 @@
 package es6_test
 
+import (
+	"strings"
+	"testing"
+
+	"github.com/crhntr/gobel/es6"
+)
+
 // var es6js = "function fibonacci(n){if(n>=2){return fibonacci(n-1)+fibonacci(n-2)}return 1};console.log(fibonacci(7))"
 //
 // func TestDecodeES6Script(t *testing.T) {
 // 	es6.DecodeES6Script(strings.NewReader(es6js))
 // }
+
+func TestDecodeES6Script_ExpressionStatements(t *testing.T) {
+	for _, src := range []string{
+		"1;",
+		"1",
+		"'a'\n/b/g\n`c`",
+		"this; null; foo;",
+		"a = {b, c: [1, , 2], d() { e; }};",
+		"[a, b] = [b, a]",
+		"({a = 1} = b)",
+		"console.log(a + b * 2, typeof c === 'string' ? c : `${c}`)\n++i",
+		"a ?? b;",
+		"a.b ||= c &&= d ??= e;",
+	} {
+		t.Run(src, func(t *testing.T) {
+			if _, err := es6.DecodeES6Script(strings.NewReader(src)); err != nil {
+				t.Errorf("unexpected error for %q: %s", src, err)
+			}
+		})
+	}
+
+	for _, tc := range []struct{ name, src string }{
+		{name: "a missing operand", src: "a +;"},
+		{name: "an unclosed argument list", src: "f(;"},
+		{name: "two expressions without a semicolon", src: "1 2;"},
+		{name: "an arrow function without a body", src: "(a, b) =>"},
+		{name: "a property without a value", src: "({a:});"},
+		{name: "an export in a script", src: "export default 1;"},
+		{name: "a reserved word as a statement in strict mode code", src: "implements;"},
+		{name: "an unsupported class declaration", src: "class A {}"},
+		{name: "an unsupported let declaration", src: "let a = 1;"},
+	} {
+		t.Run("should not allow "+tc.name, func(t *testing.T) {
+			if _, err := es6.DecodeES6Script(strings.NewReader(tc.src)); err == nil {
+				t.Errorf("expected an error for %q", tc.src)
+			}
+		})
+	}
+}
+
+func TestDecodeES6Script_Functions(t *testing.T) {
+	for _, src := range []string{
+		"function f() {}",
+		"function f(a, b = 1, ...c) { return a + b + c.length }",
+		"function f({a, b: [c, , ...d]} = {}, [e = 1]) { return }\nf()",
+		"g = function () { return function h(a) { return a } }",
+		"function f(a) { 'use strict'; return a => { return a } }",
+	} {
+		t.Run(src, func(t *testing.T) {
+			if _, err := es6.DecodeES6Script(strings.NewReader(src)); err != nil {
+				t.Errorf("unexpected error for %q: %s", src, err)
+			}
+		})
+	}
+
+	for _, tc := range []struct{ name, src string }{
+		{name: "a return statement outside of a function", src: "return 1"},
+		{name: "a function without a name", src: "function () {}"},
+		{name: "duplicate parameters with an initializer", src: "function f(a, a = 1) {}"},
+		{name: "duplicate parameters in strict mode code", src: "function f(a, a) {}"},
+		{name: "a use strict directive with a rest parameter", src: "function f(...a) { 'use strict' }"},
+		{name: "a strict function named eval", src: "function eval() { 'use strict' }"},
+		{name: "an unclosed body", src: "function f() { return"},
+	} {
+		t.Run("should not allow "+tc.name, func(t *testing.T) {
+			if _, err := es6.DecodeES6Script(strings.NewReader(tc.src)); err == nil {
+				t.Errorf("expected an error for %q", tc.src)
+			}
+		})
+	}
+}
+
+func TestDecodeES6Module(t *testing.T) {
+	for _, src := range []string{
+		"",
+		"export default function () {}",
+		"function f(a) { return a }\nexport default function g() { return f(1) }",
+		"export default a => a;\na = 1",
+	} {
+		t.Run(src, func(t *testing.T) {
+			if _, err := es6.DecodeES6Module(strings.NewReader(src)); err != nil {
+				t.Errorf("unexpected error for %q: %s", src, err)
+			}
+		})
+	}
+
+	for _, tc := range []struct{ name, src string }{
+		{name: "an unsupported import declaration", src: "import a from 'a';"},
+		{name: "an unsupported export other than export default", src: "export { a };"},
+		{name: "two default exports without a semicolon", src: "export default 1 2"},
+		{name: "await as an identifier", src: "await = 1;"},
+	} {
+		t.Run("should not allow "+tc.name, func(t *testing.T) {
+			if _, err := es6.DecodeES6Module(strings.NewReader(tc.src)); err == nil {
+				t.Errorf("expected an error for %q", tc.src)
+			}
+		})
+	}
+
+	src := "export default function () {}"
+	if _, err := es6.DecodeES6Script(strings.NewReader(src)); err == nil {
+		t.Errorf("expected an error for an export in a script %q", src)
+	}
+}
@@ -2,6 +2,9 @@ package es6
 
 import (
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -9,6 +12,8 @@ import (
 // ASTNode ...
 type ASTNode interface {
 	Positioner
+	Commenter
+	Concrete
 }
 
 // Positioner ...
@@ -16,6 +21,21 @@ type Positioner interface {
 	Position() (filename string, offset int, line int, column int)
 }
 
+// Commenter is implemented by nodes with comments attached to them. A
+// comment leads the outermost node that starts at the token after it and
+// trails the innermost node that ends at the token before it.
+type Commenter interface {
+	LeadingComments() []Token
+	TrailingComments() []Token
+}
+
+// Concrete is implemented by nodes that keep the tokens they were parsed
+// from when the Lexer's ConcreteSyntax is set. The whitespace, line
+// terminators and comments before a token are kept with it.
+type Concrete interface {
+	Tokens() []Token
+}
+
 // Parser ...
 type Parser interface {
 	Parse(l *Lexer) (ASTNode, error)
@@ -23,6 +43,52 @@ type Parser interface {
 
 type node struct {
 	FilePosition
+	leadingComments, trailingComments []Token
+	// the node spans [firstToken, endToken) of the tokens consumed, which
+	// are only kept in ConcreteSyntax mode
+	consumed             *tokenLog
+	firstToken, endToken int
+}
+
+// startNode returns a node at the position of the next token with the
+// leading comments of the next token attached
+func startNode(l *Lexer) node {
+	n := startListNode(l)
+	n.leadingComments = l.takeLeadingComments()
+	return n
+}
+
+// startListNode returns a node at the position of the next token that leaves
+// the leading comments of the next token for the first node in it
+func startListNode(l *Lexer) node {
+	return node{FilePosition: l.Peek(l.goal).FilePosition, firstToken: len(l.consumed.tokens)}
+}
+
+// finish attaches the trailing comments of the last token consumed, and the
+// range of tokens consumed since the node started when ConcreteSyntax is set
+func (n *node) finish(l *Lexer) {
+	n.trailingComments = l.takeTrailingComments()
+	if l.ConcreteSyntax {
+		n.consumed, n.endToken = l.consumed, len(l.consumed.tokens)
+	}
+}
+
+// LeadingComments returns the comments before the node
+func (n node) LeadingComments() []Token {
+	return n.leadingComments
+}
+
+// TrailingComments returns the comments after the node on the same line
+func (n node) TrailingComments() []Token {
+	return n.trailingComments
+}
+
+// Tokens returns the tokens the node was parsed from
+func (n node) Tokens() []Token {
+	if n.consumed == nil {
+		return nil
+	}
+	return n.consumed.tokens[n.firstToken:n.endToken:n.endToken]
 }
 
 // IncorrectTokenError is returned when an unexpected token is found
@@ -34,6 +100,34 @@ func (tok IncorrectTokenError) Error() string {
 	return fmt.Sprintf("IncorrectTokenError at %s", tok.FilePosition.String())
 }
 
+// isPunctuator reports whether tok is the punctuator value
+func isPunctuator(tok Token, value string) bool {
+	switch tok.Type {
+	case PunctuatorToken, RightBracePunctuatorToken, DivPunctuatorToken:
+		return tok.Value == value
+	default:
+		return false
+	}
+}
+
+// startNodeAt returns a node that starts where n starts with the tokens of
+// n, for a node whose first child is n. The comments before n stay attached
+// to it
+func startNodeAt(n ASTNode) node {
+	return n.(interface{ start() node }).start()
+}
+
+func (n node) start() node {
+	return node{FilePosition: n.FilePosition, consumed: n.consumed, firstToken: n.firstToken, endToken: n.endToken}
+}
+
+// positionOf returns the position of n
+func positionOf(n ASTNode) FilePosition {
+	var pos FilePosition
+	pos.FileName, pos.Offset, pos.Line, pos.Column = n.Position()
+	return pos
+}
+
 //
 //  A.2 Expressions
 //
@@ -44,12 +138,36 @@ func (tok IncorrectTokenError) Error() string {
 // implements: Parser and ASTNode
 type IdentifierReferenceNode struct {
 	node
+	Name string
 }
 
 // ParseIdentifierReferenceNode ...
 func ParseIdentifierReferenceNode(l *Lexer) (IdentifierReferenceNode, error) {
-	panic("ParseIdentifierReferenceNode not implemented")
-	// return nil, nil
+	n := IdentifierReferenceNode{node: startNode(l)}
+	tok, err := parseIdentifier(l)
+	if err != nil {
+		return n, err
+	}
+	n.Name = tok.Cooked
+	n.finish(l)
+	return n, nil
+}
+
+// parseIdentifier consumes an Identifier, an IdentifierName that is not a
+// ReservedWord even when it is escaped. let, static and yield are reserved
+// in strict mode code and await in module code [See 12.1.1]
+func parseIdentifier(l *Lexer) (Token, error) {
+	tok := l.Peek(InputElementRegExp)
+	if tok.Type != IdentifierNameToken {
+		return tok, IncorrectTokenError(tok)
+	}
+	if l.isReservedWord(tok.Cooked) ||
+		l.strict && isOneOf(tok.Cooked, "let", "static", "yield") ||
+		l.Module && tok.Cooked == "await" {
+		return tok, errors.Errorf("expected an Identifier but found the reserved word %q %s", tok.Cooked, tok.FilePosition)
+	}
+	l.Next(InputElementRegExp)
+	return tok, nil
 }
 
 // BindingIdentifierNode [Yield] : [See 12.1]
@@ -59,12 +177,22 @@ func ParseIdentifierReferenceNode(l *Lexer) (IdentifierReferenceNode, error) {
 // implements: Parser and ASTNode
 type BindingIdentifierNode struct {
 	node
+	Name string
 }
 
 // ParseBindingIdentifierNode ...
 func ParseBindingIdentifierNode(l *Lexer) (BindingIdentifierNode, error) {
-	panic("ParseBindingIdentifierNode not implemented")
-	// return nil, nil
+	n := BindingIdentifierNode{node: startNode(l)}
+	tok, err := parseIdentifier(l)
+	if err != nil {
+		return n, err
+	}
+	if l.strict && isOneOf(tok.Cooked, "eval", "arguments") {
+		return n, errors.Errorf("%s can not be bound in strict mode code %s", tok.Cooked, tok.FilePosition)
+	}
+	n.Name = tok.Cooked
+	n.finish(l)
+	return n, nil
 }
 
 // IdentifierNode  : [See 12.1]
@@ -77,19 +205,224 @@ type IdentifierNode struct {
 
 // ParseIdentifierNode ...
 func ParseIdentifierNode(l *Lexer) (IdentifierNode, error) {
-	n := IdentifierNode{node: node{l.CurrentPosition()}}
+	n := IdentifierNode{node: startNode(l)}
 	tt := l.Next(l.goal)
 	if tt.Type == ReservedWordToken {
 		return n, errors.Errorf("IdentifierNode must not be a ReservedWordToken found %q", tt.Value)
 	}
 	n.Name = tt.Value
+	n.finish(l)
 	return n, nil
 }
 
-// ParseCoverParenthesizedExpressionAndArrowParameterListNode ...
+// ParseCoverParenthesizedExpressionAndArrowParameterListNode parses the
+// parentheses once so that they can be refined to a
+// ParenthesizedExpressionNode or to the parameters of an arrow function
+// depending on whether => follows them
 func ParseCoverParenthesizedExpressionAndArrowParameterListNode(l *Lexer) (CoverParenthesizedExpressionAndArrowParameterListNode, error) {
-	panic("ParseCoverParenthesizedExpressionAndArrowParameterListNode not implemented")
-	// return nil, nil
+	n := CoverParenthesizedExpressionAndArrowParameterListNode{node: startNode(l)}
+	if tok := l.Peek(InputElementRegExp); !isPunctuator(tok, "(") {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementRegExp)
+	if tok := l.Peek(InputElementRegExp); !isPunctuator(tok, ")") && !isPunctuator(tok, "...") {
+		// the expressions may be parameters so a CoverInitializedName in
+		// them is only reported when they are refined to an expression
+		outer := l.coverInitializedName
+		l.coverInitializedName = nil
+		expression := ExpressionNode{node: startNode(l)}
+		for {
+			element, err := parseAssignmentExpression(l, true)
+			if err != nil {
+				return n, err
+			}
+			expression.Expressions = append(expression.Expressions, element)
+			if !isPunctuator(l.Peek(InputElementDiv), ",") || isPunctuator(l.PeekN(2, InputElementDiv), "...") {
+				break
+			}
+			l.Next(InputElementDiv)
+		}
+		expression.finish(l)
+		n.Expression = &expression
+		n.coverInitializedName, l.coverInitializedName = l.coverInitializedName, outer
+		if isPunctuator(l.Peek(InputElementDiv), ",") {
+			l.Next(InputElementDiv)
+		}
+	}
+	if isPunctuator(l.Peek(InputElementDiv), "...") {
+		rest, err := ParseBindingRestElementNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Rest = &rest
+	}
+	if tok := l.Next(InputElementDiv); !isPunctuator(tok, ")") {
+		return n, errors.Errorf("expected ')' to end the parentheses %s", tok.FilePosition)
+	}
+	n.finish(l)
+	return n, nil
+}
+
+// parenthesizedExpression refines n to the ParenthesizedExpression it
+// covers [See 12.2.1.1]
+func (n CoverParenthesizedExpressionAndArrowParameterListNode) parenthesizedExpression() (ParenthesizedExpressionNode, error) {
+	switch {
+	case n.Rest != nil:
+		return ParenthesizedExpressionNode{}, errors.Errorf("expected '=>' after the parameters with the rest parameter %s", n.Rest.FilePosition)
+	case n.Expression == nil:
+		return ParenthesizedExpressionNode{}, errors.Errorf("expected an expression or '=>' after the empty parentheses %s", n.FilePosition)
+	case n.coverInitializedName != nil:
+		return ParenthesizedExpressionNode{}, errors.Errorf("unexpected initializer in an object literal that is not assigned to %s", *n.coverInitializedName)
+	}
+	return ParenthesizedExpressionNode{node: n.node, ExpressionNode: *n.Expression}, nil
+}
+
+// arrowParameters refines n to the ArrowFormalParameters it covers
+// [See 14.2.9]
+func (n CoverParenthesizedExpressionAndArrowParameterListNode) arrowParameters(l *Lexer) (FormalParametersNode, error) {
+	parameters := FormalParametersNode{node: n.node}
+	if n.Expression != nil {
+		for _, expression := range n.Expression.Expressions {
+			element, err := refineBindingElement(l, expression)
+			if err != nil {
+				return parameters, err
+			}
+			parameters.Formals = append(parameters.Formals, FormalParameterNode{node: startNodeAt(element), Element: element})
+		}
+	}
+	if n.Rest != nil {
+		parameters.Rest = &FunctionRestParameterNode{node: startNodeAt(*n.Rest), Element: *n.Rest}
+	}
+	if name, ok := duplicateName(boundNames(parameters)); ok {
+		return parameters, errors.Errorf("duplicate parameter name %q %s", name, n.FilePosition)
+	}
+	return parameters, nil
+}
+
+// refineBindingElement refines an AssignmentExpression to the
+// BindingElement it covers, an ObjectLiteral or ArrayLiteral covers a
+// BindingPattern
+func refineBindingElement(l *Lexer, n AssignmentExpressionNode) (BindingElementNode, error) {
+	element := BindingElementNode{node: startNodeAt(n)}
+	var initializer *InitializerNode
+	switch n.Operator {
+	case "":
+	case "=":
+		value, _ := n.Value.(AssignmentExpressionNode)
+		initializer = &InitializerNode{node: startNodeAt(value), Expression: value}
+	default:
+		return element, errors.Errorf("invalid parameter %s", n.FilePosition)
+	}
+	target := n.Child
+	if primary, ok := target.(PrimaryExpressionNode); ok {
+		target = primary.Child
+	}
+	switch target := target.(type) {
+	case IdentifierReferenceNode:
+		binding, err := refineBindingIdentifier(l, target)
+		if err != nil {
+			return element, err
+		}
+		element.Child = SingleNameBindingNode{node: startNodeAt(n), Name: binding, Initializer: initializer}
+		return element, nil
+	case ObjectLiteralNode:
+		pattern, err := refineObjectBindingPattern(l, target)
+		if err != nil {
+			return element, err
+		}
+		element.Child = BindingPatternNode{node: startNodeAt(pattern), Child: pattern}
+		element.Initializer = initializer
+		return element, nil
+	case ArrayLiteralNode:
+		pattern, err := refineArrayBindingPattern(l, target)
+		if err != nil {
+			return element, err
+		}
+		element.Child = BindingPatternNode{node: startNodeAt(pattern), Child: pattern}
+		element.Initializer = initializer
+		return element, nil
+	}
+	return element, errors.Errorf("invalid parameter %s", positionOf(target))
+}
+
+// refineBindingIdentifier refines an IdentifierReference to a
+// BindingIdentifier
+func refineBindingIdentifier(l *Lexer, n IdentifierReferenceNode) (BindingIdentifierNode, error) {
+	if l.strict && isOneOf(n.Name, "eval", "arguments") {
+		return BindingIdentifierNode{}, errors.Errorf("%s can not be bound in strict mode code %s", n.Name, n.FilePosition)
+	}
+	return BindingIdentifierNode{node: n.node, Name: n.Name}, nil
+}
+
+// refineObjectBindingPattern refines an ObjectLiteral to the
+// ObjectBindingPattern it covers
+func refineObjectBindingPattern(l *Lexer, n ObjectLiteralNode) (ObjectBindingPatternNode, error) {
+	pattern := ObjectBindingPatternNode{node: n.node}
+	for _, property := range n.Properties {
+		binding := BindingPropertyNode{node: property.node}
+		switch child := property.Child.(type) {
+		case IdentifierReferenceNode:
+			name, err := refineBindingIdentifier(l, child)
+			if err != nil {
+				return pattern, err
+			}
+			single := SingleNameBindingNode{node: startNodeAt(name), Name: name}
+			binding.Element = BindingElementNode{node: startNodeAt(single), Child: single}
+		case CoverInitializedNameNode:
+			name, err := refineBindingIdentifier(l, child.Name)
+			if err != nil {
+				return pattern, err
+			}
+			initializer := child.Initializer
+			single := SingleNameBindingNode{node: child.node, Name: name, Initializer: &initializer}
+			binding.Element = BindingElementNode{node: startNodeAt(single), Child: single}
+		case PropertyNameNode:
+			name := child
+			binding.Name = &name
+			value, _ := property.Value.(AssignmentExpressionNode)
+			var err error
+			if binding.Element, err = refineBindingElement(l, value); err != nil {
+				return pattern, err
+			}
+		default:
+			return pattern, errors.Errorf("invalid parameter %s", property.FilePosition)
+		}
+		pattern.Properties = append(pattern.Properties, binding)
+	}
+	return pattern, nil
+}
+
+// refineArrayBindingPattern refines an ArrayLiteral to the
+// ArrayBindingPattern it covers
+func refineArrayBindingPattern(l *Lexer, n ArrayLiteralNode) (ArrayBindingPatternNode, error) {
+	pattern := ArrayBindingPatternNode{node: n.node}
+	for i, element := range n.Elements {
+		switch element := element.(type) {
+		case HoleNode:
+			pattern.Elements = append(pattern.Elements, element)
+		case SpreadElementNode:
+			if i != len(n.Elements)-1 || n.trailingComma {
+				return pattern, errors.Errorf("a rest element must be last in an array pattern %s", element.FilePosition)
+			}
+			reference, ok := element.Expression.Child.(PrimaryExpressionNode)
+			identifier, isIdentifier := reference.Child.(IdentifierReferenceNode)
+			if !ok || !isIdentifier || element.Expression.Operator != "" {
+				return pattern, errors.Errorf("a rest element must be an identifier %s", element.FilePosition)
+			}
+			name, err := refineBindingIdentifier(l, identifier)
+			if err != nil {
+				return pattern, err
+			}
+			pattern.Rest = &BindingRestElementNode{node: element.node, Name: name}
+		case AssignmentExpressionNode:
+			binding, err := refineBindingElement(l, element)
+			if err != nil {
+				return pattern, err
+			}
+			pattern.Elements = append(pattern.Elements, binding)
+		}
+	}
+	return pattern, nil
 }
 
 // ParenthesizedExpressionNode [Yield] : [See 12.2]
@@ -102,24 +435,11 @@ type ParenthesizedExpressionNode struct {
 
 // ParseParenthesizedExpressionNode ...
 func ParseParenthesizedExpressionNode(l *Lexer) (ParenthesizedExpressionNode, error) {
-	n := ParenthesizedExpressionNode{node: node{l.CurrentPosition()}}
-
-	tt := l.Next(l.goal)
-	if tt.Value != "(" {
-		return n, errors.New("expected '('")
-	}
-	var err error
-	n.ExpressionNode, err = ParseExpressionNode(l)
+	cover, err := ParseCoverParenthesizedExpressionAndArrowParameterListNode(l)
 	if err != nil {
-		return n, err
-	}
-
-	tt = l.Next(l.goal)
-	if tt.Value != ")" {
-		return n, errors.New("expected ')'")
+		return ParenthesizedExpressionNode{node: cover.node}, err
 	}
-
-	return n, nil
+	return cover.parenthesizedExpression()
 }
 
 // ElementListNode [Yield] : [See 12.2.5]
@@ -130,12 +450,45 @@ func ParseParenthesizedExpressionNode(l *Lexer) (ParenthesizedExpressionNode, er
 // implements: Parser and ASTNode
 type ElementListNode struct {
 	node
+	// Elements are the AssignmentExpressionNode, SpreadElementNode or
+	// HoleNode of each element
+	Elements []ASTNode
 }
 
-// ParseElementListNode ...
+// ParseElementListNode parses elements up to the commas before the ] that
+// ends the ArrayLiteral, which are left for ParseArrayLiteralNode
 func ParseElementListNode(l *Lexer) (ElementListNode, error) {
-	panic("ParseElementListNode not implemented")
-	// return nil, nil
+	n := ElementListNode{node: startNode(l)}
+	for {
+		if isPunctuator(l.Peek(InputElementRegExp), ",") {
+			elision, err := ParseElisionNode(l)
+			if err != nil {
+				return n, err
+			}
+			for _, hole := range elision.Holes {
+				n.Elements = append(n.Elements, hole)
+			}
+		}
+		var (
+			element ASTNode
+			err     error
+		)
+		if isPunctuator(l.Peek(InputElementRegExp), "...") {
+			element, err = ParseSpreadElementNode(l)
+		} else {
+			element, err = parseAssignmentExpression(l, true)
+		}
+		if err != nil {
+			return n, err
+		}
+		n.Elements = append(n.Elements, element)
+		if !isPunctuator(l.Peek(InputElementDiv), ",") || isElisionBefore(l, 2, "]") {
+			break
+		}
+		l.Next(InputElementDiv)
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // ElisionNode  : [See 12.2.5]
@@ -144,12 +497,32 @@ func ParseElementListNode(l *Lexer) (ElementListNode, error) {
 // implements: Parser and ASTNode
 type ElisionNode struct {
 	node
+	// Holes has a HoleNode for each comma
+	Holes []HoleNode
 }
 
 // ParseElisionNode ...
 func ParseElisionNode(l *Lexer) (ElisionNode, error) {
-	panic("ParseElisionNode not implemented")
-	// return nil, nil
+	n := ElisionNode{node: startNode(l)}
+	if tok := l.Peek(InputElementRegExp); !isPunctuator(tok, ",") {
+		return n, IncorrectTokenError(tok)
+	}
+	for isPunctuator(l.Peek(InputElementRegExp), ",") {
+		hole := HoleNode{node: startNode(l)}
+		l.Next(InputElementRegExp)
+		hole.finish(l)
+		n.Holes = append(n.Holes, hole)
+	}
+	n.finish(l)
+	return n, nil
+}
+
+// HoleNode is an element left out of an ArrayLiteral or an
+// ArrayBindingPattern by an Elision, it is at the position of the comma that
+// makes it
+// implements: ASTNode
+type HoleNode struct {
+	node
 }
 
 // SpreadElementNode [Yield] : [See 12.2.5]
@@ -157,12 +530,22 @@ func ParseElisionNode(l *Lexer) (ElisionNode, error) {
 // implements: Parser and ASTNode
 type SpreadElementNode struct {
 	node
+	Expression AssignmentExpressionNode
 }
 
 // ParseSpreadElementNode ...
 func ParseSpreadElementNode(l *Lexer) (SpreadElementNode, error) {
-	panic("ParseSpreadElementNode not implemented")
-	// return nil, nil
+	n := SpreadElementNode{node: startNode(l)}
+	if tok := l.Peek(InputElementRegExp); !isPunctuator(tok, "...") {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementRegExp)
+	var err error
+	if n.Expression, err = parseAssignmentExpression(l, true); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // PropertyDefinitionListNode [Yield] : [See 12.2.6]
@@ -171,12 +554,26 @@ func ParseSpreadElementNode(l *Lexer) (SpreadElementNode, error) {
 // implements: Parser and ASTNode
 type PropertyDefinitionListNode struct {
 	node
+	Properties []PropertyDefinitionNode
 }
 
-// ParsePropertyDefinitionListNode ...
+// ParsePropertyDefinitionListNode parses properties up to a comma before the
+// } that ends the ObjectLiteral, which is left for ParseObjectLiteralNode
 func ParsePropertyDefinitionListNode(l *Lexer) (PropertyDefinitionListNode, error) {
-	panic("ParsePropertyDefinitionListNode not implemented")
-	// return nil, nil
+	n := PropertyDefinitionListNode{node: startNode(l)}
+	for {
+		property, err := ParsePropertyDefinitionNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Properties = append(n.Properties, property)
+		if !isPunctuator(l.Peek(InputElementDiv), ",") || isPunctuator(l.PeekN(2, InputElementDiv), "}") {
+			break
+		}
+		l.Next(InputElementDiv)
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // PropertyDefinitionNode [Yield] : [See 12.2.6]
@@ -187,12 +584,49 @@ func ParsePropertyDefinitionListNode(l *Lexer) (PropertyDefinitionListNode, erro
 // implements: Parser and ASTNode
 type PropertyDefinitionNode struct {
 	node
+	// Child is the IdentifierReferenceNode of a shorthand property, a
+	// CoverInitializedNameNode, a MethodDefinitionNode, or the
+	// PropertyNameNode of a property with a Value
+	Child ASTNode
+	Value ASTNode
 }
 
 // ParsePropertyDefinitionNode ...
 func ParsePropertyDefinitionNode(l *Lexer) (PropertyDefinitionNode, error) {
-	panic("ParsePropertyDefinitionNode not implemented")
-	// return nil, nil
+	n := PropertyDefinitionNode{node: startNode(l)}
+	var err error
+	tok, next := l.Peek(InputElementDiv), l.PeekN(2, InputElementDiv)
+	switch {
+	case tok.Type == IdentifierNameToken && (isPunctuator(next, ",") || isPunctuator(next, "}")):
+		n.Child, err = ParseIdentifierReferenceNode(l)
+	case tok.Type == IdentifierNameToken && isPunctuator(next, "="):
+		n.Child, err = ParseCoverInitializedNameNode(l)
+	case isPunctuator(tok, "*"),
+		tok.Type == IdentifierNameToken && isOneOf(tok.Value, "get", "set") && !isPunctuator(next, ":") && !isPunctuator(next, "("):
+		n.Child, err = ParseMethodDefinitionNode(l)
+	default:
+		cp := l.Mark()
+		defer l.Release(cp)
+		var name PropertyNameNode
+		if name, err = ParsePropertyNameNode(l); err != nil {
+			return n, err
+		}
+		if isPunctuator(l.Peek(InputElementDiv), "(") {
+			l.Reset(cp)
+			n.Child, err = ParseMethodDefinitionNode(l)
+			break
+		}
+		if tok := l.Next(InputElementDiv); !isPunctuator(tok, ":") {
+			return n, errors.Errorf("expected ':' after the property name %s", tok.FilePosition)
+		}
+		n.Child = name
+		n.Value, err = parseAssignmentExpression(l, true)
+	}
+	if err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // PropertyNameNode [Yield] : [See 12.2.6]
@@ -201,12 +635,24 @@ func ParsePropertyDefinitionNode(l *Lexer) (PropertyDefinitionNode, error) {
 // implements: Parser and ASTNode
 type PropertyNameNode struct {
 	node
+	// Child is a LiteralPropertyNameNode or a ComputedPropertyNameNode
+	Child ASTNode
 }
 
 // ParsePropertyNameNode ...
 func ParsePropertyNameNode(l *Lexer) (PropertyNameNode, error) {
-	panic("ParsePropertyNameNode not implemented")
-	// return nil, nil
+	n := PropertyNameNode{node: startNode(l)}
+	var err error
+	if isPunctuator(l.Peek(InputElementDiv), "[") {
+		n.Child, err = ParseComputedPropertyNameNode(l)
+	} else {
+		n.Child, err = ParseLiteralPropertyNameNode(l)
+	}
+	if err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // LiteralPropertyNameNode  : [See 12.2.6]
@@ -216,12 +662,80 @@ func ParsePropertyNameNode(l *Lexer) (PropertyNameNode, error) {
 // implements: Parser and ASTNode
 type LiteralPropertyNameNode struct {
 	node
+	// Name is the IdentifierName, the value of the StringLiteral, or the
+	// value of the NumericLiteral converted to a String [See 12.2.6.5]
+	Name string
 }
 
 // ParseLiteralPropertyNameNode ...
 func ParseLiteralPropertyNameNode(l *Lexer) (LiteralPropertyNameNode, error) {
-	panic("ParseLiteralPropertyNameNode not implemented")
-	// return nil, nil
+	n := LiteralPropertyNameNode{node: startNode(l)}
+	tok := l.Peek(InputElementDiv)
+	switch tok.Type {
+	case IdentifierNameToken, ReservedWordToken, StringLiteralToken:
+		n.Name = tok.Cooked
+		if tok.Type == ReservedWordToken {
+			n.Name = tok.Value
+		}
+	case NumericLiteralToken:
+		if tok.BigInt != nil {
+			n.Name = tok.BigInt.String()
+		} else {
+			n.Name = numberToString(tok.Number)
+		}
+	default:
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementDiv)
+	n.finish(l)
+	return n, nil
+}
+
+// numberToString converts a Number to a String as JavaScript does
+// [See 7.1.12.1]
+func numberToString(m float64) string {
+	switch {
+	case math.IsNaN(m):
+		return "NaN"
+	case m == 0:
+		return "0"
+	case m < 0:
+		return "-" + numberToString(-m)
+	case math.IsInf(m, 1):
+		return "Infinity"
+	}
+	// the shortest digits that identify m and the exponent n where m is
+	// 0.digits × 10^n
+	e := strconv.FormatFloat(m, 'e', -1, 64)
+	mantissa, exponent := e[:strings.IndexByte(e, 'e')], e[strings.IndexByte(e, 'e')+1:]
+	digits := strings.Replace(mantissa, ".", "", 1)
+	n, _ := strconv.Atoi(exponent)
+	n++
+	k := len(digits)
+	switch {
+	case k <= n && n <= 21:
+		return digits + strings.Repeat("0", n-k)
+	case 0 < n && n <= 21:
+		return digits[:n] + "." + digits[n:]
+	case -6 < n && n <= 0:
+		return "0." + strings.Repeat("0", -n) + digits
+	}
+	sign := "+"
+	if n-1 < 0 {
+		sign = "-"
+	}
+	exponent = sign + strconv.Itoa(abs(n-1))
+	if k == 1 {
+		return digits + "e" + exponent
+	}
+	return digits[:1] + "." + digits[1:] + "e" + exponent
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
 }
 
 // ComputedPropertyNameNode [Yield] : [See 12.2.6]
@@ -229,12 +743,25 @@ func ParseLiteralPropertyNameNode(l *Lexer) (LiteralPropertyNameNode, error) {
 // implements: Parser and ASTNode
 type ComputedPropertyNameNode struct {
 	node
+	Expression AssignmentExpressionNode
 }
 
 // ParseComputedPropertyNameNode ...
 func ParseComputedPropertyNameNode(l *Lexer) (ComputedPropertyNameNode, error) {
-	panic("ParseComputedPropertyNameNode not implemented")
-	// return nil, nil
+	n := ComputedPropertyNameNode{node: startNode(l)}
+	if tok := l.Peek(InputElementDiv); !isPunctuator(tok, "[") {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementDiv)
+	var err error
+	if n.Expression, err = ParseAssignmentExpressionNode(l); err != nil {
+		return n, err
+	}
+	if tok := l.Next(InputElementDiv); !isPunctuator(tok, "]") {
+		return n, errors.Errorf("expected ']' to end the computed property name %s", tok.FilePosition)
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // CoverInitializedNameNode [Yield] : [See 12.2.6]
@@ -242,12 +769,27 @@ func ParseComputedPropertyNameNode(l *Lexer) (ComputedPropertyNameNode, error) {
 // implements: Parser and ASTNode
 type CoverInitializedNameNode struct {
 	node
+	Name        IdentifierReferenceNode
+	Initializer InitializerNode
 }
 
-// ParseCoverInitializedNameNode ...
+// ParseCoverInitializedNameNode parses a CoverInitializedName, it is an
+// error unless the ObjectLiteral it is in is reinterpreted as an
+// AssignmentPattern which ParseAssignmentExpressionNode reports
 func ParseCoverInitializedNameNode(l *Lexer) (CoverInitializedNameNode, error) {
-	panic("ParseCoverInitializedNameNode not implemented")
-	// return nil, nil
+	n := CoverInitializedNameNode{node: startNode(l)}
+	var err error
+	if n.Name, err = ParseIdentifierReferenceNode(l); err != nil {
+		return n, err
+	}
+	if n.Initializer, err = ParseInitializerNode(l); err != nil {
+		return n, err
+	}
+	if l.coverInitializedName == nil {
+		l.coverInitializedName = &n.Initializer.FilePosition
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // InitializerNode [In, Yield] : [See 12.2.6]
@@ -255,12 +797,22 @@ func ParseCoverInitializedNameNode(l *Lexer) (CoverInitializedNameNode, error) {
 // implements: Parser and ASTNode
 type InitializerNode struct {
 	node
+	Expression AssignmentExpressionNode
 }
 
 // ParseInitializerNode ...
 func ParseInitializerNode(l *Lexer) (InitializerNode, error) {
-	panic("ParseInitializerNode not implemented")
-	// return nil, nil
+	n := InitializerNode{node: startNode(l)}
+	if tok := l.Peek(InputElementDiv); !isPunctuator(tok, "=") {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementDiv)
+	var err error
+	if n.Expression, err = ParseAssignmentExpressionNode(l); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // TemplateSpansNode [Yield] : [See 12.2.9]
@@ -292,36 +844,86 @@ func ParseTemplateMiddleListNode(l *Lexer) (TemplateMiddleListNode, error) {
 }
 
 // MemberExpressionNode [Yield] : [See 12.3]
-//  PrimaryExpression[?Yield]
 //  MemberExpression[?Yield] [ Expression[In, ?Yield] ]
 //  MemberExpression[?Yield] . IdentifierName
-//  MemberExpression[?Yield] TemplateLiteral[?Yield]
-//  SuperProperty[?Yield]
-//  MetaProperty
-//  new MemberExpression[?Yield] Arguments[?Yield]
-// implements: Parser and ASTNode
+// is a property access of a MemberExpression or a CallExpression, its
+// Property is nil when it is accessed by Name
+// implements: ASTNode
 type MemberExpressionNode struct {
 	node
+	Object   ASTNode
+	Name     string
+	Property ASTNode
 }
 
-// ParseMemberExpressionNode ...
-func ParseMemberExpressionNode(l *Lexer) (MemberExpressionNode, error) {
-	panic("ParseMemberExpressionNode not implemented")
-	// return nil, nil
+// TaggedTemplateNode [Yield] : [See 12.3]
+//  MemberExpression[?Yield] TemplateLiteral[?Yield]
+//  CallExpression[?Yield] TemplateLiteral[?Yield]
+// implements: ASTNode
+type TaggedTemplateNode struct {
+	node
+	Tag      ASTNode
+	Template TemplateLiteralNode
 }
 
 // SuperPropertyNode [Yield] : [See 12.3]
 //  super [ Expression[In, ?Yield] ]
 //  super . IdentifierName
+// its Property is nil when it is accessed by Name
 // implements: Parser and ASTNode
 type SuperPropertyNode struct {
 	node
+	Name     string
+	Property ASTNode
 }
 
 // ParseSuperPropertyNode ...
 func ParseSuperPropertyNode(l *Lexer) (SuperPropertyNode, error) {
-	panic("ParseSuperPropertyNode not implemented")
-	// return nil, nil
+	n := SuperPropertyNode{node: startNode(l)}
+	if tok := l.Peek(InputElementRegExp); tok.Type != ReservedWordToken || tok.Value != "super" {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementRegExp)
+	var err error
+	switch tok := l.Next(InputElementDiv); {
+	case isPunctuator(tok, "."):
+		n.Name, err = parsePropertyIdentifierName(l)
+	case isPunctuator(tok, "["):
+		n.Property, err = parsePropertyExpression(l)
+	default:
+		err = errors.Errorf("expected '.' or '[' after super %s", tok.FilePosition)
+	}
+	if err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
+}
+
+// parsePropertyIdentifierName consumes the IdentifierName after the . of a
+// property access
+func parsePropertyIdentifierName(l *Lexer) (string, error) {
+	switch tok := l.Next(InputElementDiv); tok.Type {
+	case IdentifierNameToken:
+		return tok.Cooked, nil
+	case ReservedWordToken:
+		return tok.Value, nil
+	default:
+		return "", errors.Errorf("expected a property name after '.' %s", tok.FilePosition)
+	}
+}
+
+// parsePropertyExpression parses the Expression after the [ of a property
+// access and consumes the ] that ends it
+func parsePropertyExpression(l *Lexer) (ExpressionNode, error) {
+	n, err := ParseExpressionNode(l)
+	if err != nil {
+		return n, err
+	}
+	if tok := l.Next(InputElementDiv); !isPunctuator(tok, "]") {
+		return n, errors.Errorf("expected ']' to end the property access %s", tok.FilePosition)
+	}
+	return n, nil
 }
 
 // MetaPropertyNode  : [See 12.3]
@@ -329,12 +931,18 @@ func ParseSuperPropertyNode(l *Lexer) (SuperPropertyNode, error) {
 // implements: Parser and ASTNode
 type MetaPropertyNode struct {
 	node
+	NewTarget NewTargetNode
 }
 
 // ParseMetaPropertyNode ...
 func ParseMetaPropertyNode(l *Lexer) (MetaPropertyNode, error) {
-	panic("ParseMetaPropertyNode not implemented")
-	// return nil, nil
+	n := MetaPropertyNode{node: startNode(l)}
+	var err error
+	if n.NewTarget, err = ParseNewTargetNode(l); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // NewTargetNode  : [See 12.3]
@@ -346,40 +954,61 @@ type NewTargetNode struct {
 
 // ParseNewTargetNode ...
 func ParseNewTargetNode(l *Lexer) (NewTargetNode, error) {
-	panic("ParseNewTargetNode not implemented")
-	// return nil, nil
+	n := NewTargetNode{node: startNode(l)}
+	tok := l.Peek(InputElementRegExp)
+	if tok.Type != ReservedWordToken || tok.Value != "new" || !isPunctuator(l.PeekN(2, InputElementDiv), ".") {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementRegExp)
+	l.Next(InputElementDiv)
+	if tok := l.Next(InputElementDiv); tok.Type != IdentifierNameToken || tok.Value != "target" {
+		return n, errors.Errorf("expected target after new. %s", tok.FilePosition)
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // NewExpressionNode [Yield] : [See 12.3]
-//  MemberExpression[?Yield]
+//  new MemberExpression[?Yield] Arguments[?Yield]
 //  new NewExpression[?Yield]
+// its Arguments are nil when there are no parentheses after the Callee
 // implements: Parser and ASTNode
 type NewExpressionNode struct {
 	node
+	Callee    ASTNode
+	Arguments *ArgumentsNode
 }
 
 // ParseNewExpressionNode ...
 func ParseNewExpressionNode(l *Lexer) (NewExpressionNode, error) {
-	panic("ParseNewExpressionNode not implemented")
-	// return nil, nil
+	n := NewExpressionNode{node: startNode(l)}
+	if tok := l.Peek(InputElementRegExp); tok.Type != ReservedWordToken || tok.Value != "new" {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementRegExp)
+	var err error
+	if n.Callee, err = parseMemberExpression(l, false, nil); err != nil {
+		return n, err
+	}
+	if isPunctuator(l.Peek(InputElementDiv), "(") {
+		arguments, err := ParseArgumentsNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Arguments = &arguments
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // CallExpressionNode [Yield] : [See 12.3]
 //  MemberExpression[?Yield] Arguments[?Yield]
-//  SuperCall[?Yield]
 //  CallExpression[?Yield] Arguments[?Yield]
-//  CallExpression[?Yield] [ Expression[In, ?Yield] ]
-//  CallExpression[?Yield] . IdentifierName
-//  CallExpression[?Yield] TemplateLiteral[?Yield]
-// implements: Parser and ASTNode
+// implements: ASTNode
 type CallExpressionNode struct {
 	node
-}
-
-// ParseCallExpressionNode ...
-func ParseCallExpressionNode(l *Lexer) (CallExpressionNode, error) {
-	panic("ParseCallExpressionNode not implemented")
-	// return nil, nil
+	Callee    ASTNode
+	Arguments ArgumentsNode
 }
 
 // SuperCallNode [Yield] : [See 12.3]
@@ -387,12 +1016,22 @@ func ParseCallExpressionNode(l *Lexer) (CallExpressionNode, error) {
 // implements: Parser and ASTNode
 type SuperCallNode struct {
 	node
+	Arguments ArgumentsNode
 }
 
 // ParseSuperCallNode ...
 func ParseSuperCallNode(l *Lexer) (SuperCallNode, error) {
-	panic("ParseSuperCallNode not implemented")
-	// return nil, nil
+	n := SuperCallNode{node: startNode(l)}
+	if tok := l.Peek(InputElementRegExp); tok.Type != ReservedWordToken || tok.Value != "super" {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementRegExp)
+	var err error
+	if n.Arguments, err = ParseArgumentsNode(l); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // ArgumentsNode [Yield] : [See 12.3]
@@ -401,12 +1040,30 @@ func ParseSuperCallNode(l *Lexer) (SuperCallNode, error) {
 // implements: Parser and ASTNode
 type ArgumentsNode struct {
 	node
+	// List has an AssignmentExpressionNode or a SpreadElementNode for each
+	// argument
+	List []ASTNode
 }
 
 // ParseArgumentsNode ...
 func ParseArgumentsNode(l *Lexer) (ArgumentsNode, error) {
-	panic("ParseArgumentsNode not implemented")
-	// return nil, nil
+	n := ArgumentsNode{node: startNode(l)}
+	if tok := l.Peek(InputElementDiv); !isPunctuator(tok, "(") {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementDiv)
+	if !isPunctuator(l.Peek(InputElementRegExp), ")") {
+		list, err := ParseArgumentListNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.List = list.List
+	}
+	if tok := l.Next(InputElementDiv); !isPunctuator(tok, ")") {
+		return n, errors.Errorf("expected ')' to end the arguments %s", tok.FilePosition)
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // ArgumentListNode [Yield] : [See 12.3]
@@ -417,12 +1074,38 @@ func ParseArgumentsNode(l *Lexer) (ArgumentsNode, error) {
 // implements: Parser and ASTNode
 type ArgumentListNode struct {
 	node
+	List []ASTNode
 }
 
 // ParseArgumentListNode ...
 func ParseArgumentListNode(l *Lexer) (ArgumentListNode, error) {
-	panic("ParseArgumentListNode not implemented")
-	// return nil, nil
+	n := ArgumentListNode{node: startNode(l)}
+	for {
+		var argument ASTNode
+		if isPunctuator(l.Peek(InputElementRegExp), "...") {
+			spread := SpreadElementNode{node: startNode(l)}
+			l.Next(InputElementRegExp)
+			var err error
+			if spread.Expression, err = ParseAssignmentExpressionNode(l); err != nil {
+				return n, err
+			}
+			spread.finish(l)
+			argument = spread
+		} else {
+			expression, err := ParseAssignmentExpressionNode(l)
+			if err != nil {
+				return n, err
+			}
+			argument = expression
+		}
+		n.List = append(n.List, argument)
+		if !isPunctuator(l.Peek(InputElementDiv), ",") {
+			break
+		}
+		l.Next(InputElementDiv)
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // LeftHandSideExpressionNode [Yield] : [See 12.3]
@@ -431,227 +1114,343 @@ func ParseArgumentListNode(l *Lexer) (ArgumentListNode, error) {
 // implements: Parser and ASTNode
 type LeftHandSideExpressionNode struct {
 	node
+	// Child is the expression, such as a MemberExpressionNode, a
+	// CallExpressionNode or a PrimaryExpressionNode
+	Child ASTNode
 }
 
 // ParseLeftHandSideExpressionNode ...
 func ParseLeftHandSideExpressionNode(l *Lexer) (LeftHandSideExpressionNode, error) {
-	panic("ParseLeftHandSideExpressionNode not implemented")
-	// return nil, nil
+	n := LeftHandSideExpressionNode{node: startNode(l)}
+	var err error
+	if n.Child, err = parseMemberExpression(l, true, nil); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
+}
+
+// parseMemberExpression parses a MemberExpression, or when calls is set a
+// LeftHandSideExpression, into the nodes of its property accesses, calls
+// and tagged templates around the PrimaryExpressionNode, NewExpressionNode,
+// SuperPropertyNode, SuperCallNode or NewTargetNode it starts with. first is
+// the PrimaryExpressionNode it starts with when that was already parsed, or
+// nil
+func parseMemberExpression(l *Lexer, calls bool, first ASTNode) (ASTNode, error) {
+	var (
+		expr = first
+		err  error
+	)
+	switch tok := l.Peek(InputElementRegExp); {
+	case first != nil:
+	case tok.Type == ReservedWordToken && tok.Value == "new":
+		if isPunctuator(l.PeekN(2, InputElementDiv), ".") {
+			expr, err = ParseNewTargetNode(l)
+		} else {
+			expr, err = ParseNewExpressionNode(l)
+		}
+	case tok.Type == ReservedWordToken && tok.Value == "super":
+		if calls && isPunctuator(l.PeekN(2, InputElementDiv), "(") {
+			expr, err = ParseSuperCallNode(l)
+		} else {
+			expr, err = ParseSuperPropertyNode(l)
+		}
+	default:
+		expr, err = ParsePrimaryExpressionNode(l)
+	}
+	if err != nil {
+		return expr, err
+	}
+	for {
+		switch tok := l.Peek(InputElementDiv); {
+		case isPunctuator(tok, "."):
+			n := MemberExpressionNode{node: startNodeAt(expr), Object: expr}
+			l.Next(InputElementDiv)
+			if n.Name, err = parsePropertyIdentifierName(l); err != nil {
+				return expr, err
+			}
+			n.finish(l)
+			expr = n
+		case isPunctuator(tok, "["):
+			n := MemberExpressionNode{node: startNodeAt(expr), Object: expr}
+			l.Next(InputElementDiv)
+			if n.Property, err = parsePropertyExpression(l); err != nil {
+				return expr, err
+			}
+			n.finish(l)
+			expr = n
+		case tok.Type == NoSubstitutionTemplateToken, tok.Type == TemplateHeadToken:
+			n := TaggedTemplateNode{node: startNodeAt(expr), Tag: expr}
+			if n.Template, err = parseTemplateLiteral(l, true); err != nil {
+				return expr, err
+			}
+			n.finish(l)
+			expr = n
+		case calls && isPunctuator(tok, "("):
+			n := CallExpressionNode{node: startNodeAt(expr), Callee: expr}
+			if n.Arguments, err = ParseArgumentsNode(l); err != nil {
+				return expr, err
+			}
+			n.finish(l)
+			expr = n
+		default:
+			return expr, nil
+		}
+	}
 }
 
-// PostfixExpressionNode [Yield] : [See 12.4]
-//  LeftHandSideExpression[?Yield]
+// UpdateExpressionNode [Yield] : [See 12.4 and 12.5]
 //  LeftHandSideExpression[?Yield] [no LineTerminator here] ++
 //  LeftHandSideExpression[?Yield] [no LineTerminator here] --
-// implements: Parser and ASTNode
-type PostfixExpressionNode struct {
+//  ++ UnaryExpression[?Yield]
+//  -- UnaryExpression[?Yield]
+// implements: ASTNode
+type UpdateExpressionNode struct {
 	node
-}
-
-// ParsePostfixExpressionNode ...
-func ParsePostfixExpressionNode(l *Lexer) (PostfixExpressionNode, error) {
-	panic("ParsePostfixExpressionNode not implemented")
-	// return nil, nil
+	Operator string
+	Prefix   bool
+	Operand  ASTNode
 }
 
 // UnaryExpressionNode [Yield] : [See 12.5]
-//  PostfixExpression[?Yield]
 //  delete UnaryExpression[?Yield]
 //  void UnaryExpression[?Yield]
 //  typeof UnaryExpression[?Yield]
-//  ++ UnaryExpression[?Yield]
-//  -- UnaryExpression[?Yield]
 //  + UnaryExpression[?Yield]
 //  - UnaryExpression[?Yield]
 //  ~ UnaryExpression[?Yield]
 //  ! UnaryExpression[?Yield]
-// implements: Parser and ASTNode
+// implements: ASTNode
 type UnaryExpressionNode struct {
 	node
+	Operator string
+	Operand  ASTNode
+}
+
+// parseUnaryExpression parses a UnaryExpression into an
+// UnaryExpressionNode, an UpdateExpressionNode or the node of its
+// LeftHandSideExpression. first is as for parseMemberExpression
+func parseUnaryExpression(l *Lexer, first ASTNode) (ASTNode, error) {
+	tok := l.Peek(InputElementRegExp)
+	switch {
+	case first != nil:
+	case tok.Type == ReservedWordToken && isOneOf(tok.Value, "delete", "void", "typeof"),
+		tok.Type == PunctuatorToken && isOneOf(tok.Value, "+", "-", "~", "!"):
+		n := UnaryExpressionNode{node: startNode(l), Operator: tok.Value}
+		l.Next(InputElementRegExp)
+		var err error
+		if n.Operand, err = parseUnaryExpression(l, nil); err != nil {
+			return n, err
+		}
+		if _, ok := unparenthesized(n.Operand).(IdentifierReferenceNode); ok && l.strict && n.Operator == "delete" {
+			return n, errors.Errorf("can not delete an identifier in strict mode code %s", n.FilePosition)
+		}
+		n.finish(l)
+		return n, nil
+	case isPunctuator(tok, "++"), isPunctuator(tok, "--"):
+		n := UpdateExpressionNode{node: startNode(l), Operator: tok.Value, Prefix: true}
+		l.Next(InputElementRegExp)
+		var err error
+		if n.Operand, err = parseUnaryExpression(l, nil); err != nil {
+			return n, err
+		}
+		if err = checkAssignmentTarget(l, n.Operand, false); err != nil {
+			return n, err
+		}
+		n.finish(l)
+		return n, nil
+	}
+	operand, err := parseMemberExpression(l, true, first)
+	if err != nil {
+		return operand, err
+	}
+	if tok := l.Peek(InputElementDiv); (isPunctuator(tok, "++") || isPunctuator(tok, "--")) && !tok.NewlineBefore {
+		if err = checkAssignmentTarget(l, operand, false); err != nil {
+			return operand, err
+		}
+		n := UpdateExpressionNode{node: startNodeAt(operand), Operator: tok.Value, Operand: operand}
+		l.Next(InputElementDiv)
+		n.finish(l)
+		return n, nil
+	}
+	return operand, nil
 }
 
-// ParseUnaryExpressionNode ...
-func ParseUnaryExpressionNode(l *Lexer) (UnaryExpressionNode, error) {
-	panic("ParseUnaryExpressionNode not implemented")
-	// return nil, nil
+// unparenthesized returns the expression in the parentheses around n
+func unparenthesized(n ASTNode) ASTNode {
+	for {
+		switch e := n.(type) {
+		case PrimaryExpressionNode:
+			n = e.Child
+		case ParenthesizedExpressionNode:
+			if len(e.Expressions) != 1 {
+				return n
+			}
+			n = e.Expressions[0]
+		case AssignmentExpressionNode:
+			if e.Operator != "" {
+				return n
+			}
+			n = e.Child
+		default:
+			return n
+		}
+	}
 }
 
-// MultiplicativeExpressionNode [Yield] : [See 12.6]
-//  UnaryExpression[?Yield]
+// BinaryExpressionNode [In, Yield] : [See 12.6 to 12.11]
 //  MultiplicativeExpression[?Yield] MultiplicativeOperator UnaryExpression[?Yield]
-// implements: Parser and ASTNode
-type MultiplicativeExpressionNode struct {
-	node
-}
-
-// ParseMultiplicativeExpressionNode ...
-func ParseMultiplicativeExpressionNode(l *Lexer) (MultiplicativeExpressionNode, error) {
-	panic("ParseMultiplicativeExpressionNode not implemented")
-	// return nil, nil
-}
-
-// MultiplicativeOperatorNode  : one of [See 12.6]
-//  * / %
-// implements: Parser and ASTNode
-type MultiplicativeOperatorNode struct {
-	node
-}
-
-// ParseMultiplicativeOperatorNode ...
-func ParseMultiplicativeOperatorNode(l *Lexer) (MultiplicativeOperatorNode, error) {
-	panic("ParseMultiplicativeOperatorNode not implemented")
-	// return nil, nil
-}
-
-// AdditiveExpressionNode [Yield] : [See 12.7]
-//  MultiplicativeExpression[?Yield]
 //  AdditiveExpression[?Yield] + MultiplicativeExpression[?Yield]
 //  AdditiveExpression[?Yield] - MultiplicativeExpression[?Yield]
-// implements: Parser and ASTNode
-type AdditiveExpressionNode struct {
-	node
-}
-
-// ParseAdditiveExpressionNode ...
-func ParseAdditiveExpressionNode(l *Lexer) (AdditiveExpressionNode, error) {
-	panic("ParseAdditiveExpressionNode not implemented")
-	// return nil, nil
-}
-
-// ShiftExpressionNode [Yield] : [See 12.8]
-//  AdditiveExpression[?Yield]
 //  ShiftExpression[?Yield] << AdditiveExpression[?Yield]
 //  ShiftExpression[?Yield] >> AdditiveExpression[?Yield]
 //  ShiftExpression[?Yield] >>> AdditiveExpression[?Yield]
-// implements: Parser and ASTNode
-type ShiftExpressionNode struct {
-	node
-}
-
-// ParseShiftExpressionNode ...
-func ParseShiftExpressionNode(l *Lexer) (ShiftExpressionNode, error) {
-	panic("ParseShiftExpressionNode not implemented")
-	// return nil, nil
-}
-
-// RelationalExpressionNode [In, Yield] : [See 12.9]
-//  ShiftExpression[?Yield]
 //  RelationalExpression[?In, ?Yield] < ShiftExpression[?Yield]
 //  RelationalExpression[?In, ?Yield] > ShiftExpression[?Yield]
 //  RelationalExpression[?In, ?Yield] <= ShiftExpression[? Yield]
 //  RelationalExpression[?In, ?Yield] >= ShiftExpression[?Yield]
 //  RelationalExpression[?In, ?Yield] instanceof ShiftExpression[?Yield]
 //  [+In] RelationalExpression[In, ?Yield] in ShiftExpression[?Yield]
-// implements: Parser and ASTNode
-type RelationalExpressionNode struct {
-	node
-}
-
-// ParseRelationalExpressionNode ...
-func ParseRelationalExpressionNode(l *Lexer) (RelationalExpressionNode, error) {
-	panic("ParseRelationalExpressionNode not implemented")
-	// return nil, nil
-}
-
-// EqualityExpressionNode [In, Yield] : [See 12.10]
-//  RelationalExpression[?In, ?Yield]
 //  EqualityExpression[?In, ?Yield] == RelationalExpression[?In, ?Yield]
 //  EqualityExpression[?In, ?Yield] != RelationalExpression[?In, ?Yield]
 //  EqualityExpression[?In, ?Yield] === RelationalExpression[?In, ?Yield]
 //  EqualityExpression[?In, ?Yield] !== RelationalExpression[?In, ?Yield]
-// implements: Parser and ASTNode
-type EqualityExpressionNode struct {
-	node
-}
-
-// ParseEqualityExpressionNode ...
-func ParseEqualityExpressionNode(l *Lexer) (EqualityExpressionNode, error) {
-	panic("ParseEqualityExpressionNode not implemented")
-	// return nil, nil
-}
-
-// BitwiseANDExpressionNode [In, Yield] : [See 12.11]
-//  EqualityExpression[?In, ?Yield]
 //  BitwiseANDExpression[?In, ?Yield] & EqualityExpression[?In, ?Yield]
-// implements: Parser and ASTNode
-type BitwiseANDExpressionNode struct {
-	node
-}
-
-// ParseBitwiseANDExpressionNode ...
-func ParseBitwiseANDExpressionNode(l *Lexer) (BitwiseANDExpressionNode, error) {
-	panic("ParseBitwiseANDExpressionNode not implemented")
-	// return nil, nil
-}
-
-// BitwiseXORExpressionNode [In, Yield] : [See 12.11]
-//  BitwiseANDExpression[?In, ?Yield]
 //  BitwiseXORExpression[?In, ?Yield] ^ BitwiseANDExpression[?In, ?Yield]
-// implements: Parser and ASTNode
-type BitwiseXORExpressionNode struct {
-	node
-}
-
-// ParseBitwiseXORExpressionNode ...
-func ParseBitwiseXORExpressionNode(l *Lexer) (BitwiseXORExpressionNode, error) {
-	panic("ParseBitwiseXORExpressionNode not implemented")
-	// return nil, nil
-}
-
-// BitwiseORExpressionNode [In, Yield] : [See 12.11]
-//  BitwiseXORExpression[?In, ?Yield]
 //  BitwiseORExpression[?In, ?Yield] | BitwiseXORExpression[?In, ?Yield]
-// implements: Parser and ASTNode
-type BitwiseORExpressionNode struct {
+// implements: ASTNode
+type BinaryExpressionNode struct {
 	node
+	Operator    string
+	Left, Right ASTNode
 }
 
-// ParseBitwiseORExpressionNode ...
-func ParseBitwiseORExpressionNode(l *Lexer) (BitwiseORExpressionNode, error) {
-	panic("ParseBitwiseORExpressionNode not implemented")
-	// return nil, nil
-}
-
-// LogicalANDExpressionNode [In, Yield] : [See 12.12]
-//  BitwiseORExpression[?In, ?Yield]
+// LogicalExpressionNode [In, Yield] : [See 12.12]
 //  LogicalANDExpression[?In, ?Yield] && BitwiseORExpression[?In, ?Yield]
-// implements: Parser and ASTNode
-type LogicalANDExpressionNode struct {
-	node
-}
-
-// ParseLogicalANDExpressionNode ...
-func ParseLogicalANDExpressionNode(l *Lexer) (LogicalANDExpressionNode, error) {
-	panic("ParseLogicalANDExpressionNode not implemented")
-	// return nil, nil
+//  LogicalORExpression[?In, ?Yield] || LogicalANDExpression[?In, ?Yield]
+//  CoalesceExpressionHead[?In, ?Yield] ?? BitwiseORExpression[?In, ?Yield]
+// implements: ASTNode
+type LogicalExpressionNode struct {
+	node
+	Operator    string
+	Left, Right ASTNode
+}
+
+// binaryPrecedence is the precedence of each binary operator, an operator
+// binds its operands tighter than the operators with a lower precedence
+var binaryPrecedence = map[string]int{
+	"||": 1, "??": 1,
+	"&&": 2,
+	"|":  3,
+	"^":  4,
+	"&":  5,
+	"==": 6, "!=": 6, "===": 6, "!==": 6,
+	"<": 7, ">": 7, "<=": 7, ">=": 7, "instanceof": 7, "in": 7,
+	"<<": 8, ">>": 8, ">>>": 8,
+	"+": 9, "-": 9,
+	"*": 10, "/": 10, "%": 10,
+	"**": 11,
+}
+
+// binaryOperator returns the precedence of tok as a binary operator, it is
+// 0 when tok is not one. in is only an operator when the [In] grammar
+// parameter is set
+func binaryOperator(tok Token, in bool) int {
+	switch tok.Type {
+	case PunctuatorToken, DivPunctuatorToken:
+	case ReservedWordToken:
+		if tok.Value == "in" && !in {
+			return 0
+		}
+	default:
+		return 0
+	}
+	return binaryPrecedence[tok.Value]
 }
 
-// LogicalORExpressionNode [In, Yield] : [See 12.12]
-//  LogicalANDExpression[?In, ?Yield]
-//  LogicalORExpression[?In, ?Yield] || LogicalANDExpression[?In, ?Yield]
-// implements: Parser and ASTNode
-type LogicalORExpressionNode struct {
-	node
+// parseBinaryExpression parses the operators with a precedence of at least
+// min by precedence climbing into BinaryExpressionNode and
+// LogicalExpressionNode nodes. All operators are left associative except
+// for ** [See 12.6]. first is as for parseMemberExpression
+func parseBinaryExpression(l *Lexer, min int, in bool, first ASTNode) (ASTNode, error) {
+	left, err := parseUnaryExpression(l, first)
+	if err != nil {
+		return left, err
+	}
+	for {
+		tok := l.Peek(InputElementDiv)
+		precedence := binaryOperator(tok, in)
+		if precedence == 0 || precedence < min {
+			return left, nil
+		}
+		next := precedence + 1
+		if tok.Value == "**" {
+			if _, ok := left.(UnaryExpressionNode); ok {
+				return left, errors.Errorf("the operand of ** must not be a unary expression without parentheses %s", tok.FilePosition)
+			}
+			next = precedence
+		}
+		start := startNodeAt(left)
+		l.Next(InputElementDiv)
+		right, err := parseBinaryExpression(l, next, in, nil)
+		if err != nil {
+			return left, err
+		}
+		if mixesCoalesce(tok.Value, left) || mixesCoalesce(tok.Value, right) {
+			return left, errors.Errorf("?? can not be mixed with || or && without parentheses %s", tok.FilePosition)
+		}
+		if isOneOf(tok.Value, "||", "&&", "??") {
+			n := LogicalExpressionNode{node: start, Operator: tok.Value, Left: left, Right: right}
+			n.finish(l)
+			left = n
+		} else {
+			n := BinaryExpressionNode{node: start, Operator: tok.Value, Left: left, Right: right}
+			n.finish(l)
+			left = n
+		}
+	}
 }
 
-// ParseLogicalORExpressionNode ...
-func ParseLogicalORExpressionNode(l *Lexer) (LogicalORExpressionNode, error) {
-	panic("ParseLogicalORExpressionNode not implemented")
-	// return nil, nil
+// mixesCoalesce reports whether operand is a LogicalExpression that can not
+// be an operand of operator without parentheses, ?? may not be mixed with
+// || or &&
+func mixesCoalesce(operator string, operand ASTNode) bool {
+	n, ok := operand.(LogicalExpressionNode)
+	if !ok || n.Operator == operator {
+		return false
+	}
+	return operator == "??" || n.Operator == "??"
 }
 
 // ConditionalExpressionNode [In, Yield] : [See 12.13]
-//  LogicalORExpression[?In, ?Yield]
 //  LogicalORExpression[?In,?Yield] ? AssignmentExpression[In, ?Yield] : AssignmentExpression[?In, ?Yield]
-// implements: Parser and ASTNode
+// implements: ASTNode
 type ConditionalExpressionNode struct {
 	node
+	Test, Consequent, Alternate ASTNode
 }
 
-// ParseConditionalExpressionNode ...
-func ParseConditionalExpressionNode(l *Lexer) (ConditionalExpressionNode, error) {
-	panic("ParseConditionalExpressionNode not implemented")
-	// return nil, nil
+// parseConditionalExpression parses a ConditionalExpression into a
+// ConditionalExpressionNode or the node of its LogicalORExpression. first is
+// as for parseMemberExpression
+func parseConditionalExpression(l *Lexer, in bool, first ASTNode) (ASTNode, error) {
+	test, err := parseBinaryExpression(l, 1, in, first)
+	if err != nil || !isPunctuator(l.Peek(InputElementDiv), "?") {
+		return test, err
+	}
+	n := ConditionalExpressionNode{node: startNodeAt(test), Test: test}
+	l.Next(InputElementDiv)
+	if n.Consequent, err = parseAssignmentExpressionNode(l, true); err != nil {
+		return n, err
+	}
+	if tok := l.Next(InputElementDiv); !isPunctuator(tok, ":") {
+		return n, errors.Errorf("expected ':' in the conditional expression %s", tok.FilePosition)
+	}
+	if n.Alternate, err = parseAssignmentExpressionNode(l, in); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // AssignmentExpressionNode [In, Yield] : [See 12.14]
@@ -663,16 +1462,216 @@ func ParseConditionalExpressionNode(l *Lexer) (ConditionalExpressionNode, error)
 // implements: Parser and ASTNode
 type AssignmentExpressionNode struct {
 	node
+	// Child is the expression, such as a BinaryExpressionNode or a
+	// PrimaryExpressionNode
+	Child ASTNode
+	// Operator is = or an AssignmentOperator when the Child is assigned the
+	// Value, an ObjectLiteral or ArrayLiteral assigned with = is an
+	// AssignmentPattern
+	Operator string
+	Value    ASTNode
 }
 
 // ParseAssignmentExpressionNode ...
 func ParseAssignmentExpressionNode(l *Lexer) (AssignmentExpressionNode, error) {
-	panic("ParseAssignmentExpressionNode not implemented")
-	// return nil, nil
+	return parseAssignmentExpressionNode(l, true)
+}
+
+// parseAssignmentExpressionNode parses an AssignmentExpression, in is the
+// [In] grammar parameter
+func parseAssignmentExpressionNode(l *Lexer, in bool) (AssignmentExpressionNode, error) {
+	outer := l.coverInitializedName
+	l.coverInitializedName = nil
+	n, err := parseAssignmentExpression(l, in)
+	if err == nil && l.coverInitializedName != nil {
+		err = errors.Errorf("unexpected initializer in an object literal that is not assigned to %s", *l.coverInitializedName)
+	}
+	l.coverInitializedName = outer
+	return n, err
+}
+
+// parseAssignmentExpression parses an AssignmentExpression in an
+// ObjectLiteral or ArrayLiteral, which may be a target in an
+// AssignmentPattern. A CoverInitializedName in it is left for the
+// ParseAssignmentExpressionNode around the literal to report
+func parseAssignmentExpression(l *Lexer, in bool) (AssignmentExpressionNode, error) {
+	outer := l.coverInitializedName
+	l.coverInitializedName = nil
+	defer func() {
+		if l.coverInitializedName == nil {
+			l.coverInitializedName = outer
+		}
+	}()
+	n := AssignmentExpressionNode{node: startNode(l)}
+	var (
+		first ASTNode
+		err   error
+	)
+	switch tok := l.Peek(InputElementRegExp); {
+	case tok.Type == IdentifierNameToken && isPunctuator(l.PeekN(2, InputElementDiv), "=>"):
+		if n.Child, err = ParseArrowFunctionNode(l); err != nil {
+			return n, err
+		}
+		n.finish(l)
+		return n, nil
+	case isPunctuator(tok, "("):
+		cover, err := ParseCoverParenthesizedExpressionAndArrowParameterListNode(l)
+		if err != nil {
+			return n, err
+		}
+		if isPunctuator(l.Peek(InputElementDiv), "=>") {
+			parameters := ArrowParametersNode{node: startNodeAt(cover)}
+			if parameters.FormalParametersNode, err = cover.arrowParameters(l); err != nil {
+				return n, err
+			}
+			if n.Child, err = parseArrowFunction(l, parameters, in); err != nil {
+				return n, err
+			}
+			n.finish(l)
+			return n, nil
+		}
+		parenthesized, err := cover.parenthesizedExpression()
+		if err != nil {
+			return n, err
+		}
+		primary := PrimaryExpressionNode{node: startNodeAt(parenthesized), Child: parenthesized}
+		primary.finish(l)
+		first = primary
+	}
+	if n.Child, err = parseConditionalExpression(l, in, first); err != nil {
+		return n, err
+	}
+	if tok := l.Peek(InputElementDiv); isPunctuator(tok, "=") {
+		if err = checkAssignmentTarget(l, n.Child, true); err != nil {
+			return n, err
+		}
+		l.coverInitializedName = nil
+		l.Next(InputElementDiv)
+		n.Operator = "="
+	} else if isAssignmentOperator(tok) {
+		if err = checkAssignmentTarget(l, n.Child, false); err != nil {
+			return n, err
+		}
+		op, err := ParseAssignmentOperatorNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Operator = op.Operator
+	} else {
+		n.finish(l)
+		return n, nil
+	}
+	if n.Value, err = parseAssignmentExpressionNode(l, in); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
+}
+
+// checkAssignmentTarget returns an error when n can not be assigned to. An
+// ObjectLiteral or ArrayLiteral is reinterpreted as an AssignmentPattern
+// when pattern is set [See 12.14.1]
+func checkAssignmentTarget(l *Lexer, n ASTNode, pattern bool) error {
+	switch n := n.(type) {
+	case AssignmentExpressionNode:
+		if n.Operator == "" {
+			return checkAssignmentTarget(l, n.Child, pattern)
+		}
+	case PrimaryExpressionNode:
+		return checkAssignmentTarget(l, n.Child, pattern)
+	case ParenthesizedExpressionNode:
+		if len(n.Expressions) == 1 {
+			return checkAssignmentTarget(l, n.Expressions[0], false)
+		}
+	case MemberExpressionNode, SuperPropertyNode:
+		return nil
+	case IdentifierReferenceNode:
+		if l.strict && isOneOf(n.Name, "eval", "arguments") {
+			return errors.Errorf("%s can not be assigned to in strict mode code %s", n.Name, n.FilePosition)
+		}
+		return nil
+	case ObjectLiteralNode:
+		if pattern {
+			return checkObjectAssignmentPattern(l, n)
+		}
+	case ArrayLiteralNode:
+		if pattern {
+			return checkArrayAssignmentPattern(l, n)
+		}
+	}
+	return errors.Errorf("invalid assignment target %s", positionOf(n))
+}
+
+// checkObjectAssignmentPattern returns an error when n is not an
+// ObjectAssignmentPattern [See 12.14.5]
+func checkObjectAssignmentPattern(l *Lexer, n ObjectLiteralNode) error {
+	for _, property := range n.Properties {
+		switch child := property.Child.(type) {
+		case IdentifierReferenceNode:
+			if err := checkAssignmentTarget(l, child, false); err != nil {
+				return err
+			}
+		case CoverInitializedNameNode:
+			if err := checkAssignmentTarget(l, child.Name, false); err != nil {
+				return err
+			}
+		case PropertyNameNode:
+			if err := checkAssignmentElement(l, property.Value); err != nil {
+				return err
+			}
+		default:
+			return errors.Errorf("invalid assignment target %s", property.FilePosition)
+		}
+	}
+	return nil
+}
+
+// checkArrayAssignmentPattern returns an error when n is not an
+// ArrayAssignmentPattern [See 12.14.5]
+func checkArrayAssignmentPattern(l *Lexer, n ArrayLiteralNode) error {
+	for i, element := range n.Elements {
+		switch element := element.(type) {
+		case HoleNode:
+		case SpreadElementNode:
+			if i != len(n.Elements)-1 || n.trailingComma {
+				return errors.Errorf("a rest element must be last in an array pattern %s", element.FilePosition)
+			}
+			if err := checkAssignmentTarget(l, element.Expression, true); err != nil {
+				return err
+			}
+		default:
+			if err := checkAssignmentElement(l, element); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkAssignmentElement returns an error when n is not a
+// DestructuringAssignmentTarget with an optional Initializer, the target of
+// an assignment with = was checked when it was parsed
+func checkAssignmentElement(l *Lexer, n ASTNode) error {
+	if n, ok := n.(AssignmentExpressionNode); ok && n.Operator == "=" {
+		return nil
+	}
+	return checkAssignmentTarget(l, n, true)
+}
+
+// isAssignmentOperator reports whether tok is an AssignmentOperator
+func isAssignmentOperator(tok Token) bool {
+	for _, op := range assignmentOperators {
+		if isPunctuator(tok, op) {
+			return true
+		}
+	}
+	return false
 }
 
+var assignmentOperators = []string{"*=", "/=", "%=", "+=", "-=", "<<=", ">>=", ">>>=", "&=", "^=", "|=", "**=", "&&=", "||=", "??="}
+
 // AssignmentOperatorNode  : one of [See 12.14]
-//  *= /= %= += -= <<= >>= >>>= &= ^= |=
+//  *= /= %= += -= <<= >>= >>>= &= ^= |= **= &&= ||= ??=
 // implements: Parser and ASTNode
 type AssignmentOperatorNode struct {
 	node
@@ -681,24 +1680,19 @@ type AssignmentOperatorNode struct {
 
 // ParseAssignmentOperatorNode ...
 func ParseAssignmentOperatorNode(l *Lexer) (AssignmentOperatorNode, error) {
-	n := AssignmentOperatorNode{node: node{l.CurrentPosition()}}
+	n := AssignmentOperatorNode{node: startNode(l)}
 
-	err := errors.New("Assignment operation expected one of: *= /= %= += -= <<= >>= >>>= &= ^= |=")
-	tok := l.Next(l.goal)
-	if tok.Type != PunctuatorToken {
+	err := errors.New("Assignment operation expected one of: *= /= %= += -= <<= >>= >>>= &= ^= |= **= &&= ||= ??=")
+	tok := l.Next(InputElementDiv)
+	if !isAssignmentOperator(tok) {
 		return n, err
 	}
-
-	for _, op := range []string{"*=", "/=", "%=", "+=", "-=", "<=", ">>=", ">>>=", "&=", "^=", "|"} {
-		if tok.Value == op {
-			n.Operator = op
-			break
-		}
-	}
+	n.Operator = tok.Value
 
 	if n.Operator == "" {
 		return n, err
 	}
+	n.finish(l)
 	return n, nil
 }
 
@@ -708,20 +1702,33 @@ func ParseAssignmentOperatorNode(l *Lexer) (AssignmentOperatorNode, error) {
 // implements: Parser and ASTNode
 type ExpressionNode struct {
 	node
-	isThis bool
-	child  ASTNode
+	// Expressions are the expressions separated by commas
+	Expressions []AssignmentExpressionNode
 }
 
 // ParseExpressionNode ...
-func ParseExpressionNode(l *Lexer) (node ExpressionNode, err error) {
-	if tok := l.Peek(l.goal); tok.Type == ReservedWordToken && tok.Value == "this" {
-		node.isThis = true
-		return node, nil
-	}
-	if child, err := ParseAssignmentExpressionNode(l); err == nil {
-		node.child = child
+func ParseExpressionNode(l *Lexer) (ExpressionNode, error) {
+	return parseExpression(l, true)
+}
+
+// parseExpression parses an Expression, in is the [In] grammar parameter
+// which is not set for the first expression in the head of a for statement
+// so that for (a in b) is not read as the expression a in b
+func parseExpression(l *Lexer, in bool) (ExpressionNode, error) {
+	n := ExpressionNode{node: startNode(l)}
+	for {
+		expression, err := parseAssignmentExpressionNode(l, in)
+		if err != nil {
+			return n, err
+		}
+		n.Expressions = append(n.Expressions, expression)
+		if !isPunctuator(l.Peek(InputElementDiv), ",") {
+			break
+		}
+		l.Next(InputElementDiv)
 	}
-	return node, err
+	n.finish(l)
+	return n, nil
 }
 
 //
@@ -751,38 +1758,48 @@ type StatementNode struct {
 
 // ParseStatementNode ...
 func ParseStatementNode(l *Lexer) (node StatementNode, err error) {
-	defer func() { node.FilePosition = l.CurrentPosition() }()
-	if node.child, err = ParseExpressionStatementNode(l); err == nil {
-		return
-	} else if node.child, err = ParseBlockStatementNode(l); err == nil {
-		return
-	} else if node.child, err = ParseVariableStatementNode(l); err == nil {
-		return
-	} else if node.child, err = ParseIfStatementNode(l); err == nil {
-		return
-	} else if node.child, err = ParseBreakableStatementNode(l); err == nil {
-		return
-	} else if node.child, err = ParseContinueStatementNode(l); err == nil {
-		return
-	} else if node.child, err = ParseBreakStatementNode(l); err == nil {
-		return
-	} else if node.child, err = ParseReturnStatementNode(l); err == nil {
-		return
-	} else if node.child, err = ParseWithStatementNode(l); err == nil {
-		return
-	} else if node.child, err = ParseLabelledStatementNode(l); err == nil {
-		return
-	} else if node.child, err = ParseThrowStatementNode(l); err == nil {
-		return
-	} else if node.child, err = ParseTryStatementNode(l); err == nil {
-		return
-	} else if node.child, err = ParseDebuggerStatementNode(l); err == nil {
-		return
-	} else if node.child, err = ParseEmptyStatementNode(l); err == nil {
-		return
-	} else {
-		return node, err
+	node.node = startNode(l)
+	defer func() {
+		if err == nil {
+			node.finish(l)
+		}
+	}()
+	tok := l.Peek(InputElementRegExp)
+	keyword := ""
+	if tok.Type == ReservedWordToken {
+		keyword = tok.Value
+	}
+	switch {
+	case isPunctuator(tok, "{"):
+		node.child, err = ParseBlockStatementNode(l)
+	case isPunctuator(tok, ";"):
+		node.child, err = ParseEmptyStatementNode(l)
+	case keyword == "var":
+		node.child, err = ParseVariableStatementNode(l)
+	case keyword == "if":
+		node.child, err = ParseIfStatementNode(l)
+	case isOneOf(keyword, "do", "while", "for", "switch"):
+		node.child, err = ParseBreakableStatementNode(l)
+	case keyword == "continue":
+		node.child, err = ParseContinueStatementNode(l)
+	case keyword == "break":
+		node.child, err = ParseBreakStatementNode(l)
+	case keyword == "return":
+		node.child, err = ParseReturnStatementNode(l)
+	case keyword == "with":
+		node.child, err = ParseWithStatementNode(l)
+	case keyword == "throw":
+		node.child, err = ParseThrowStatementNode(l)
+	case keyword == "try":
+		node.child, err = ParseTryStatementNode(l)
+	case keyword == "debugger":
+		node.child, err = ParseDebuggerStatementNode(l)
+	case tok.Type == IdentifierNameToken && isPunctuator(l.PeekN(2, InputElementDiv), ":"):
+		node.child, err = ParseLabelledStatementNode(l)
+	default:
+		node.child, err = ParseExpressionStatementNode(l)
 	}
+	return node, err
 }
 
 // DeclarationNode [Yield] : [See clause 13]
@@ -797,15 +1814,42 @@ type DeclarationNode struct {
 
 // ParseDeclarationNode ...
 func ParseDeclarationNode(l *Lexer) (node DeclarationNode, err error) {
-	defer func() { node.FilePosition = l.CurrentPosition() }()
-	if node.child, err = ParseHoistableDeclarationNode(l); err == nil {
-		return
-	} else if node.child, err = ParseClassDeclarationNode(l); err == nil {
-		return
-	} else if node.child, err = ParseLexicalDeclarationNode(l); err == nil {
-		return
+	node.node = startNode(l)
+	defer func() {
+		if err == nil {
+			node.finish(l)
+		}
+	}()
+	tok := l.Peek(InputElementRegExp)
+	keyword := ""
+	if tok.Type == ReservedWordToken || tok.Contextual {
+		keyword = tok.Value
+	}
+	switch keyword {
+	case "function":
+		node.child, err = ParseHoistableDeclarationNode(l)
+	case "class":
+		node.child, err = ParseClassDeclarationNode(l)
+	case "let", "const":
+		node.child, err = ParseLexicalDeclarationNode(l)
+	default:
+		err = errors.Errorf("expected a declaration but got %q %s", tok.Value, tok.FilePosition)
+	}
+	return node, err
+}
+
+// startsDeclaration reports whether the StatementListItem beginning with tok
+// is a Declaration. let is only a LetOrConst when a binding follows it
+func startsDeclaration(l *Lexer, tok Token) bool {
+	switch {
+	case tok.Type == ReservedWordToken && isOneOf(tok.Value, "function", "class", "const", "let"):
+		return true
+	case tok.Contextual && tok.Value == "let":
+		next := l.PeekN(2, InputElementDiv)
+		return next.Type == IdentifierNameToken || isPunctuator(next, "[") || isPunctuator(next, "{")
+	default:
+		return false
 	}
-	return
 }
 
 // HoistableDeclarationNode [Yield, Default] : [See clause 13]
@@ -819,8 +1863,22 @@ type HoistableDeclarationNode struct {
 
 // ParseHoistableDeclarationNode ...
 func ParseHoistableDeclarationNode(l *Lexer) (node HoistableDeclarationNode, err error) {
-	defer func() { node.FilePosition = l.CurrentPosition() }()
-	if node.child, err = ParseFunctionDeclarationNode(l); err == nil {
+	return parseHoistableDeclaration(l, false)
+}
+
+// parseHoistableDeclaration parses a HoistableDeclaration, def is the
+// [Default] grammar parameter
+func parseHoistableDeclaration(l *Lexer, def bool) (node HoistableDeclarationNode, err error) {
+	node.node = startNode(l)
+	defer func() {
+		if err == nil {
+			node.finish(l)
+		}
+	}()
+	if node.child, err = parseFunctionDeclaration(l, def); err == nil {
+		return
+	}
+	if _, ok := err.(IncorrectTokenError); !ok {
 		return
 	}
 	node.child, err = ParseGeneratorDeclarationNode(l)
@@ -878,8 +1936,12 @@ type StatementListNode struct {
 
 // ParseStatementListNode ...
 func ParseStatementListNode(l *Lexer) (StatementListNode, error) {
-	node := StatementListNode{}
+	node := StatementListNode{node: startListNode(l)}
 	for {
+		if tok := l.Peek(InputElementRegExp); tok.Type == EOFToken || tok.Type == RightBracePunctuatorToken {
+			node.finish(l)
+			return node, nil
+		}
 		child, err := ParseStatementListItemNode(l)
 		if err != nil {
 			return node, err
@@ -899,12 +1961,18 @@ type StatementListItemNode struct {
 
 // ParseStatementListItemNode ...
 func ParseStatementListItemNode(l *Lexer) (node StatementListItemNode, err error) {
-	defer func() { node.FilePosition = l.CurrentPosition() }()
-	if node.child, err = ParseStatementNode(l); err == nil {
-		return
+	node.node = startNode(l)
+	defer func() {
+		if err == nil {
+			node.finish(l)
+		}
+	}()
+	if startsDeclaration(l, l.Peek(InputElementRegExp)) {
+		node.child, err = ParseDeclarationNode(l)
+	} else {
+		node.child, err = ParseStatementNode(l)
 	}
-	node.child, err = ParseDeclarationNode(l)
-	return
+	return node, err
 }
 
 // LexicalDeclarationNode [In, Yield] : [See 13.3.1]
@@ -916,8 +1984,8 @@ type LexicalDeclarationNode struct {
 
 // ParseLexicalDeclarationNode ...
 func ParseLexicalDeclarationNode(l *Lexer) (LexicalDeclarationNode, error) {
-	panic("ParseLexicalDeclarationNode not implemented")
-	// return nil, nil
+	tok := l.Peek(InputElementRegExp)
+	return LexicalDeclarationNode{}, errors.Errorf("let and const declarations are not supported %s", tok.FilePosition)
 }
 
 // LetOrConstNode  : [See 13.3.1]
@@ -931,11 +1999,12 @@ type LetOrConstNode struct {
 
 // ParseLetOrConstNode ...
 func ParseLetOrConstNode(l *Lexer) (LetOrConstNode, error) {
-	n := LetOrConstNode{node: node{l.CurrentPosition()}}
+	n := LetOrConstNode{node: startNode(l)}
 
 	tok := l.Next(l.goal)
 	if tok.Value == "const" || tok.Value == "let" {
 		n.Value = tok.Value
+		n.finish(l)
 		return n, nil
 	}
 	return n, errors.New("expected const or let")
@@ -1016,12 +2085,27 @@ func ParseVariableDeclarationNode(l *Lexer) (VariableDeclarationNode, error) {
 // implements: Parser and ASTNode
 type BindingPatternNode struct {
 	node
+	// Child is an ObjectBindingPatternNode or an ArrayBindingPatternNode
+	Child ASTNode
 }
 
 // ParseBindingPatternNode ...
 func ParseBindingPatternNode(l *Lexer) (BindingPatternNode, error) {
-	panic("ParseBindingPatternNode not implemented")
-	// return nil, nil
+	n := BindingPatternNode{node: startNode(l)}
+	var err error
+	switch tok := l.Peek(InputElementDiv); {
+	case isPunctuator(tok, "{"):
+		n.Child, err = ParseObjectBindingPatternNode(l)
+	case isPunctuator(tok, "["):
+		n.Child, err = ParseArrayBindingPatternNode(l)
+	default:
+		return n, IncorrectTokenError(tok)
+	}
+	if err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // ObjectBindingPatternNode [Yield] : [See 13.3.3]
@@ -1031,12 +2115,31 @@ func ParseBindingPatternNode(l *Lexer) (BindingPatternNode, error) {
 // implements: Parser and ASTNode
 type ObjectBindingPatternNode struct {
 	node
+	Properties []BindingPropertyNode
 }
 
 // ParseObjectBindingPatternNode ...
 func ParseObjectBindingPatternNode(l *Lexer) (ObjectBindingPatternNode, error) {
-	panic("ParseObjectBindingPatternNode not implemented")
-	// return nil, nil
+	n := ObjectBindingPatternNode{node: startNode(l)}
+	if tok := l.Peek(InputElementDiv); !isPunctuator(tok, "{") {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementDiv)
+	if !isPunctuator(l.Peek(InputElementDiv), "}") {
+		list, err := ParseBindingPropertyListNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Properties = list.Properties
+		if isPunctuator(l.Peek(InputElementDiv), ",") {
+			l.Next(InputElementDiv)
+		}
+	}
+	if tok := l.Next(InputElementDiv); !isPunctuator(tok, "}") {
+		return n, errors.Errorf("expected '}' to end the object binding pattern %s", tok.FilePosition)
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // ArrayBindingPatternNode [Yield] : [See 13.3.3]
@@ -1046,12 +2149,50 @@ func ParseObjectBindingPatternNode(l *Lexer) (ObjectBindingPatternNode, error) {
 // implements: Parser and ASTNode
 type ArrayBindingPatternNode struct {
 	node
+	// Elements has a BindingElementNode or a HoleNode for each element
+	// before the rest element Rest
+	Elements []ASTNode
+	Rest     *BindingRestElementNode
 }
 
 // ParseArrayBindingPatternNode ...
 func ParseArrayBindingPatternNode(l *Lexer) (ArrayBindingPatternNode, error) {
-	panic("ParseArrayBindingPatternNode not implemented")
-	// return nil, nil
+	n := ArrayBindingPatternNode{node: startNode(l)}
+	if tok := l.Peek(InputElementDiv); !isPunctuator(tok, "[") {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementDiv)
+	if !isElisionBefore(l, 1, "]") && !isElisionBefore(l, 1, "...") {
+		list, err := ParseBindingElementListNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Elements = list.Elements
+		if isPunctuator(l.Peek(InputElementDiv), ",") {
+			l.Next(InputElementDiv)
+		}
+	}
+	if isPunctuator(l.Peek(InputElementDiv), ",") {
+		elision, err := ParseElisionNode(l)
+		if err != nil {
+			return n, err
+		}
+		for _, hole := range elision.Holes {
+			n.Elements = append(n.Elements, hole)
+		}
+	}
+	if isPunctuator(l.Peek(InputElementDiv), "...") {
+		rest, err := ParseBindingRestElementNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Rest = &rest
+	}
+	if tok := l.Next(InputElementDiv); !isPunctuator(tok, "]") {
+		return n, errors.Errorf("expected ']' to end the array binding pattern %s", tok.FilePosition)
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // BindingPropertyListNode [Yield] : [See 13.3.3]
@@ -1060,12 +2201,27 @@ func ParseArrayBindingPatternNode(l *Lexer) (ArrayBindingPatternNode, error) {
 // implements: Parser and ASTNode
 type BindingPropertyListNode struct {
 	node
+	Properties []BindingPropertyNode
 }
 
-// ParseBindingPropertyListNode ...
+// ParseBindingPropertyListNode parses properties up to a comma before the }
+// that ends the ObjectBindingPattern, which is left for
+// ParseObjectBindingPatternNode
 func ParseBindingPropertyListNode(l *Lexer) (BindingPropertyListNode, error) {
-	panic("ParseBindingPropertyListNode not implemented")
-	// return nil, nil
+	n := BindingPropertyListNode{node: startNode(l)}
+	for {
+		property, err := ParseBindingPropertyNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Properties = append(n.Properties, property)
+		if !isPunctuator(l.Peek(InputElementDiv), ",") || isPunctuator(l.PeekN(2, InputElementDiv), "}") {
+			break
+		}
+		l.Next(InputElementDiv)
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // BindingElementListNode [Yield] : [See 13.3.3]
@@ -1074,12 +2230,31 @@ func ParseBindingPropertyListNode(l *Lexer) (BindingPropertyListNode, error) {
 // implements: Parser and ASTNode
 type BindingElementListNode struct {
 	node
+	// Elements has a BindingElementNode or a HoleNode for each element
+	Elements []ASTNode
 }
 
-// ParseBindingElementListNode ...
+// ParseBindingElementListNode parses elements up to the commas before the ]
+// or the rest element that ends the ArrayBindingPattern, which are left for
+// ParseArrayBindingPatternNode
 func ParseBindingElementListNode(l *Lexer) (BindingElementListNode, error) {
-	panic("ParseBindingElementListNode not implemented")
-	// return nil, nil
+	n := BindingElementListNode{node: startNode(l)}
+	for {
+		element, err := ParseBindingElisionElementNode(l)
+		if err != nil {
+			return n, err
+		}
+		for _, hole := range element.Holes {
+			n.Elements = append(n.Elements, hole)
+		}
+		n.Elements = append(n.Elements, element.Element)
+		if !isPunctuator(l.Peek(InputElementDiv), ",") || isElisionBefore(l, 2, "]") || isElisionBefore(l, 2, "...") {
+			break
+		}
+		l.Next(InputElementDiv)
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // BindingElisionElementNode [Yield] : [See 13.3.3]
@@ -1087,12 +2262,27 @@ func ParseBindingElementListNode(l *Lexer) (BindingElementListNode, error) {
 // implements: Parser and ASTNode
 type BindingElisionElementNode struct {
 	node
+	// Holes has a HoleNode for each comma of the Elision before the Element
+	Holes   []HoleNode
+	Element BindingElementNode
 }
 
 // ParseBindingElisionElementNode ...
 func ParseBindingElisionElementNode(l *Lexer) (BindingElisionElementNode, error) {
-	panic("ParseBindingElisionElementNode not implemented")
-	// return nil, nil
+	n := BindingElisionElementNode{node: startNode(l)}
+	if isPunctuator(l.Peek(InputElementDiv), ",") {
+		elision, err := ParseElisionNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Holes = elision.Holes
+	}
+	var err error
+	if n.Element, err = ParseBindingElementNode(l); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // BindingPropertyNode [Yield] : [See 13.3.3]
@@ -1101,12 +2291,37 @@ func ParseBindingElisionElementNode(l *Lexer) (BindingElisionElementNode, error)
 // implements: Parser and ASTNode
 type BindingPropertyNode struct {
 	node
+	// Name is nil for a SingleNameBinding, which is then the Child of the
+	// Element
+	Name    *PropertyNameNode
+	Element BindingElementNode
 }
 
 // ParseBindingPropertyNode ...
 func ParseBindingPropertyNode(l *Lexer) (BindingPropertyNode, error) {
-	panic("ParseBindingPropertyNode not implemented")
-	// return nil, nil
+	n := BindingPropertyNode{node: startNode(l)}
+	if tok := l.Peek(InputElementDiv); tok.Type == IdentifierNameToken && !isPunctuator(l.PeekN(2, InputElementDiv), ":") {
+		single, err := ParseSingleNameBindingNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Element = BindingElementNode{node: startNodeAt(single), Child: single}
+		n.finish(l)
+		return n, nil
+	}
+	name, err := ParsePropertyNameNode(l)
+	if err != nil {
+		return n, err
+	}
+	n.Name = &name
+	if tok := l.Next(InputElementDiv); !isPunctuator(tok, ":") {
+		return n, errors.Errorf("expected ':' after the property name in the object binding pattern %s", tok.FilePosition)
+	}
+	if n.Element, err = ParseBindingElementNode(l); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // BindingElementNode [Yield] : [See 13.3.3]
@@ -1115,12 +2330,32 @@ func ParseBindingPropertyNode(l *Lexer) (BindingPropertyNode, error) {
 // implements: Parser and ASTNode
 type BindingElementNode struct {
 	node
+	// Child is a SingleNameBindingNode or a BindingPatternNode, the
+	// Initializer is only set for a BindingPatternNode
+	Child       ASTNode
+	Initializer *InitializerNode
 }
 
 // ParseBindingElementNode ...
 func ParseBindingElementNode(l *Lexer) (BindingElementNode, error) {
-	panic("ParseBindingElementNode not implemented")
-	// return nil, nil
+	n := BindingElementNode{node: startNode(l)}
+	var err error
+	if tok := l.Peek(InputElementDiv); isPunctuator(tok, "[") || isPunctuator(tok, "{") {
+		if n.Child, err = ParseBindingPatternNode(l); err != nil {
+			return n, err
+		}
+		if isPunctuator(l.Peek(InputElementDiv), "=") {
+			initializer, err := ParseInitializerNode(l)
+			if err != nil {
+				return n, err
+			}
+			n.Initializer = &initializer
+		}
+	} else if n.Child, err = ParseSingleNameBindingNode(l); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // SingleNameBindingNode [Yield] : [See 13.3.3]
@@ -1128,12 +2363,26 @@ func ParseBindingElementNode(l *Lexer) (BindingElementNode, error) {
 // implements: Parser and ASTNode
 type SingleNameBindingNode struct {
 	node
+	Name        BindingIdentifierNode
+	Initializer *InitializerNode
 }
 
 // ParseSingleNameBindingNode ...
 func ParseSingleNameBindingNode(l *Lexer) (SingleNameBindingNode, error) {
-	panic("ParseSingleNameBindingNode not implemented")
-	// return nil, nil
+	n := SingleNameBindingNode{node: startNode(l)}
+	var err error
+	if n.Name, err = ParseBindingIdentifierNode(l); err != nil {
+		return n, err
+	}
+	if isPunctuator(l.Peek(InputElementDiv), "=") {
+		initializer, err := ParseInitializerNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Initializer = &initializer
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // BindingRestElementNode [Yield] : [See 13.3.3]
@@ -1141,12 +2390,22 @@ func ParseSingleNameBindingNode(l *Lexer) (SingleNameBindingNode, error) {
 // implements: Parser and ASTNode
 type BindingRestElementNode struct {
 	node
+	Name BindingIdentifierNode
 }
 
 // ParseBindingRestElementNode ...
 func ParseBindingRestElementNode(l *Lexer) (BindingRestElementNode, error) {
-	panic("ParseBindingRestElementNode not implemented")
-	// return nil, nil
+	n := BindingRestElementNode{node: startNode(l)}
+	if tok := l.Peek(InputElementDiv); !isPunctuator(tok, "...") {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementDiv)
+	var err error
+	if n.Name, err = ParseBindingIdentifierNode(l); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // EmptyStatementNode  : [See 13.4]
@@ -1172,18 +2431,21 @@ type ExpressionStatementNode struct {
 
 // ParseExpressionStatementNode ...
 func ParseExpressionStatementNode(l *Lexer) (node ExpressionStatementNode, err error) {
-	tok := l.Peek(InputElementDiv)
+	node.node = startNode(l)
+	tok := l.Peek(InputElementRegExp)
 	switch tok.Type {
 	case ReservedWordToken:
 		switch tok.Value {
 		case "function", "class":
 			return node, IncorrectTokenError(tok)
-		case "let":
-			tok2 := l.Peek(InputElementDiv)
+		default:
+		}
+	case IdentifierNameToken:
+		if tok.Contextual && tok.Value == "let" {
+			tok2 := l.PeekN(2, InputElementDiv)
 			if tok2.Type == PunctuatorToken && tok2.Value == "[" {
-				return node, IncorrectTokenError(tok2)
+				return node, IncorrectTokenError(tok)
 			}
-		default:
 		}
 	case PunctuatorToken:
 		if tok.Type == PunctuatorToken && tok.Value == "{" {
@@ -1195,12 +2457,29 @@ func ParseExpressionStatementNode(l *Lexer) (node ExpressionStatementNode, err e
 	if err != nil {
 		return node, err
 	}
-	tok3 := l.Peek(InputElementDiv)
-	if tok3.Type != PunctuatorToken || tok3.Value != ";" {
-		return node, IncorrectTokenError(tok)
+	if err = parseSemicolon(l); err != nil {
+		return node, err
+	}
+	node.finish(l)
+	return node, nil
+}
+
+// parseSemicolon consumes the ; that ends a statement. When there is none it
+// is inserted before a } or the end of the input, or a token on a new line
+// [See 11.9.1]
+func parseSemicolon(l *Lexer) error {
+	tok := l.Peek(InputElementDiv)
+	switch {
+	case tok.Type == PunctuatorToken && tok.Value == ";":
+		l.Next(InputElementDiv)
+		return nil
+	case tok.Type == ErrorToken:
+		return errors.Errorf("%s %s", tok.Value, tok.FilePosition)
+	case tok.Type == RightBracePunctuatorToken, tok.Type == EOFToken, tok.NewlineBefore:
+		return nil
+	default:
+		return IncorrectTokenError(tok)
 	}
-	node.FilePosition = l.CurrentPosition()
-	return node, err
 }
 
 // IfStatementNode [Yield, Return] : [See 13.6]
@@ -1278,7 +2557,7 @@ type ContinueStatementNode struct {
 
 // ParseContinueStatementNode ...
 func ParseContinueStatementNode(l *Lexer) (ContinueStatementNode, error) {
-	node := ContinueStatementNode{}
+	node := ContinueStatementNode{node: startNode(l)}
 	if token := l.Next(l.goal); token.Type != ReservedWordToken || token.Value != "continue" {
 		return node, errors.Errorf("expected keyword %q", "continue")
 	}
@@ -1287,6 +2566,7 @@ func ParseContinueStatementNode(l *Lexer) (ContinueStatementNode, error) {
 		l.Next(l.goal)
 		node.LabelIdentifier = idToken.Value
 	}
+	node.finish(l)
 	return node, nil
 }
 
@@ -1310,12 +2590,33 @@ func ParseBreakStatementNode(l *Lexer) (BreakStatementNode, error) {
 // implements: Parser and ASTNode
 type ReturnStatementNode struct {
 	node
+	// Expression is nil for a return without a value
+	Expression *ExpressionNode
 }
 
 // ParseReturnStatementNode ...
 func ParseReturnStatementNode(l *Lexer) (ReturnStatementNode, error) {
-	panic("ParseReturnStatementNode not implemented")
-	// return nil, nil
+	n := ReturnStatementNode{node: startNode(l)}
+	tok := l.Peek(InputElementRegExp)
+	if tok.Type != ReservedWordToken || tok.Value != "return" {
+		return n, IncorrectTokenError(tok)
+	}
+	if !l.inFunctionBody {
+		return n, errors.Errorf("a return statement must be in the body of a function %s", tok.FilePosition)
+	}
+	l.Next(InputElementRegExp)
+	if next := l.Peek(InputElementRegExp); !isPunctuator(next, ";") && !isPunctuator(next, "}") && next.Type != EOFToken && !next.NewlineBefore {
+		expression, err := ParseExpressionNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Expression = &expression
+	}
+	if err := parseSemicolon(l); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // WithStatementNode [Yield, Return] : [See 13.11]
@@ -1515,7 +2816,8 @@ func ParseDebuggerStatementNode(l *Lexer) (DebuggerStatementNode, error) {
 //  [+Default] function ( FormalParameters ) { FunctionBody }
 // implements: Parser and ASTNode
 type FunctionDeclarationNode struct {
-	BindingIdentifier BindingIdentifierNode
+	// BindingIdentifier is nil for the function of an export default
+	BindingIdentifier *BindingIdentifierNode
 	FormalParameters  FormalParametersNode
 	FunctionBody      FunctionBodyNode
 	node
@@ -1523,41 +2825,99 @@ type FunctionDeclarationNode struct {
 
 // ParseFunctionDeclarationNode ...
 func ParseFunctionDeclarationNode(l *Lexer) (FunctionDeclarationNode, error) {
+	return parseFunctionDeclaration(l, false)
+}
+
+// parseFunctionDeclaration parses a FunctionDeclaration, def is the
+// [Default] grammar parameter that lets the name be left out
+func parseFunctionDeclaration(l *Lexer, def bool) (FunctionDeclarationNode, error) {
+	n := FunctionDeclarationNode{node: startNode(l)}
+	tok := l.Peek(InputElementRegExp)
+	if tok.Type != ReservedWordToken || tok.Value != "function" || isPunctuator(l.PeekN(2, InputElementDiv), "*") {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementRegExp)
+	if !def || !isPunctuator(l.Peek(InputElementDiv), "(") {
+		name, err := parseFunctionName(l)
+		if err != nil {
+			return n, err
+		}
+		n.BindingIdentifier = &name
+	}
+	var err error
+	if n.FormalParameters, n.FunctionBody, err = parseFunctionParametersAndBody(l, n.BindingIdentifier); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
+}
+
+// parseFunctionName parses the BindingIdentifier after function
+func parseFunctionName(l *Lexer) (BindingIdentifierNode, error) {
+	tok := l.Peek(InputElementDiv)
+	name, err := ParseBindingIdentifierNode(l)
+	if _, ok := err.(IncorrectTokenError); ok {
+		return name, errors.Errorf("expected the name of the function %s", tok.FilePosition)
+	}
+	return name, err
+}
+
+// parseFunctionParametersAndBody parses the parenthesized parameters and the
+// body in braces of a function, name is nil for a function without one
+func parseFunctionParametersAndBody(l *Lexer, name *BindingIdentifierNode) (FormalParametersNode, FunctionBodyNode, error) {
 	var (
-		node = FunctionDeclarationNode{}
-		// err error
+		parameters FormalParametersNode
+		body       FunctionBodyNode
+		err        error
 	)
-	tokPeek0 := l.Peek(InputElementDiv)
-	if tokPeek0.Type != ReservedWordToken {
-		return node, IncorrectTokenError(tokPeek0)
-	}
-	if tokPeek0.Value != "function" {
-		return node, IncorrectTokenError(tokPeek0)
-	}
-	l.Next(InputElementDiv) // accept input token
-
-	tokPeek1 := l.Peek(InputElementDiv)
-	node.FilePosition = l.CurrentPosition()
-	fmt.Println(tokPeek1)
-	fmt.Println(node)
-	fmt.Println()
-	panic("ParseFunctionDeclarationNode not implemented")
-	// // if next token is not "(" attempt BindingIdentifier
-	// if !(tokPeek1.Type == PunctuatorToken && tokPeek0.Value == "(") {
-	// 	var identifierChild ASTNode
-	// 	identifierChild, err = ParseBindingIdentifierNode(l)
-	// 	if err != nil {
-	// 		_, isIncorrectToken := err.(IncorrectTokenError)
-	// 		if !isIncorrectToken {
-	// 			return nil, err
-	// 		}
-	// 	}
-	// 	var ok bool
-	// 	if node.BindingIdentifier, ok = identifierChild.(BindingIdentifierNode); ok {
-	// 		return node, IncorrectTokenError(tokPeek1)
-	// 	}
-	// }
-	// return nil, nil
+	if tok := l.Next(InputElementDiv); !isPunctuator(tok, "(") {
+		return parameters, body, errors.Errorf("expected '(' to start the parameters of the function %s", tok.FilePosition)
+	}
+	if parameters, err = ParseFormalParametersNode(l); err != nil {
+		return parameters, body, err
+	}
+	if tok := l.Next(InputElementDiv); !isPunctuator(tok, ")") {
+		return parameters, body, errors.Errorf("expected ')' to end the parameters of the function %s", tok.FilePosition)
+	}
+	if tok := l.Next(InputElementDiv); !isPunctuator(tok, "{") {
+		return parameters, body, errors.Errorf("expected '{' to start the body of the function %s", tok.FilePosition)
+	}
+	if body, err = ParseFunctionBodyNode(l); err != nil {
+		return parameters, body, err
+	}
+	if tok := l.Next(InputElementDiv); !isPunctuator(tok, "}") {
+		return parameters, body, errors.Errorf("expected '}' to end the body of the function %s", tok.FilePosition)
+	}
+	return parameters, body, checkFunction(l, name, parameters, body, false)
+}
+
+// checkFunction applies the early errors that depend on both the parameters
+// and the body of a function, unique is set for parameters that must not
+// bind the same name twice in any code [See 14.1.2]
+func checkFunction(l *Lexer, name *BindingIdentifierNode, parameters FormalParametersNode, body FunctionBodyNode, unique bool) error {
+	simple := parameters.IsSimpleParameterList()
+	if body.UseStrict && !simple {
+		return errors.Errorf("a function with a 'use strict' directive must have a simple parameter list %s", body.FilePosition)
+	}
+	names := boundNames(parameters)
+	if l.strict || body.UseStrict || !simple || unique {
+		if duplicate, ok := duplicateName(names); ok {
+			return errors.Errorf("duplicate parameter name %q %s", duplicate, parameters.FilePosition)
+		}
+	}
+	if body.UseStrict && !l.strict {
+		// the names were bound before the body made the function strict
+		// mode code
+		if name != nil {
+			names = append(names, name.Name)
+		}
+		for _, bound := range names {
+			if isOneOf(bound, "eval", "arguments", "let", "static", "yield") || isOneOf(bound, futureResdervedWordsStrict...) {
+				return errors.Errorf("%s can not be bound in strict mode code %s", bound, parameters.FilePosition)
+			}
+		}
+	}
+	return nil
 }
 
 // StrictFormalParametersNode [Yield] : [See 14.1]
@@ -1565,12 +2925,22 @@ func ParseFunctionDeclarationNode(l *Lexer) (FunctionDeclarationNode, error) {
 // implements: Parser and ASTNode
 type StrictFormalParametersNode struct {
 	node
+	FormalParametersNode
 }
 
-// ParseStrictFormalParametersNode ...
+// ParseStrictFormalParametersNode parses FormalParameters that must not
+// bind the same name twice [See 14.1.2]
 func ParseStrictFormalParametersNode(l *Lexer) (StrictFormalParametersNode, error) {
-	panic("ParseStrictFormalParametersNode not implemented")
-	// return nil, nil
+	n := StrictFormalParametersNode{node: startNode(l)}
+	var err error
+	if n.FormalParametersNode, err = ParseFormalParametersNode(l); err != nil {
+		return n, err
+	}
+	if name, ok := duplicateName(boundNames(n.FormalParametersNode)); ok {
+		return n, errors.Errorf("duplicate parameter name %q %s", name, n.FilePosition)
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // FormalParametersNode [Yield] : [See 14.1]
@@ -1579,12 +2949,84 @@ func ParseStrictFormalParametersNode(l *Lexer) (StrictFormalParametersNode, erro
 // implements: Parser and ASTNode
 type FormalParametersNode struct {
 	node
+	// Formals are the parameters before the rest parameter Rest
+	Formals []FormalParameterNode
+	Rest    *FunctionRestParameterNode
+}
+
+// IsSimpleParameterList reports whether the parameters are only identifiers
+// without initializers or a rest parameter [See 14.1.13]
+func (n FormalParametersNode) IsSimpleParameterList() bool {
+	if n.Rest != nil {
+		return false
+	}
+	for _, formal := range n.Formals {
+		if single, ok := formal.Element.Child.(SingleNameBindingNode); !ok || single.Initializer != nil {
+			return false
+		}
+	}
+	return true
 }
 
 // ParseFormalParametersNode ...
 func ParseFormalParametersNode(l *Lexer) (FormalParametersNode, error) {
-	panic("ParseFormalParametersNode not implemented")
-	// return nil, nil
+	n := FormalParametersNode{node: startNode(l)}
+	if !isPunctuator(l.Peek(InputElementDiv), ")") {
+		list, err := ParseFormalParameterListNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Formals, n.Rest = list.Formals, list.Rest
+	}
+	n.finish(l)
+	return n, nil
+}
+
+// boundNames returns the names bound by parameters or a binding
+// [See 13.3.3.1 and 14.1.3]
+func boundNames(n ASTNode) []string {
+	var names []string
+	switch n := n.(type) {
+	case BindingIdentifierNode:
+		names = append(names, n.Name)
+	case SingleNameBindingNode:
+		names = append(names, n.Name.Name)
+	case BindingElementNode:
+		names = boundNames(n.Child)
+	case BindingPatternNode:
+		names = boundNames(n.Child)
+	case ObjectBindingPatternNode:
+		for _, property := range n.Properties {
+			names = append(names, boundNames(property.Element)...)
+		}
+	case ArrayBindingPatternNode:
+		for _, element := range n.Elements {
+			names = append(names, boundNames(element)...)
+		}
+		if n.Rest != nil {
+			names = append(names, n.Rest.Name.Name)
+		}
+	case FormalParametersNode:
+		for _, formal := range n.Formals {
+			names = append(names, boundNames(formal.Element)...)
+		}
+		if n.Rest != nil {
+			names = append(names, n.Rest.Element.Name.Name)
+		}
+	}
+	return names
+}
+
+// duplicateName returns the first name that is in names more than once
+func duplicateName(names []string) (string, bool) {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			return name, true
+		}
+		seen[name] = true
+	}
+	return "", false
 }
 
 // FormalParameterListNode [Yield] : [See 14.1]
@@ -1594,12 +3036,32 @@ func ParseFormalParametersNode(l *Lexer) (FormalParametersNode, error) {
 // implements: Parser and ASTNode
 type FormalParameterListNode struct {
 	node
+	Formals []FormalParameterNode
+	Rest    *FunctionRestParameterNode
 }
 
 // ParseFormalParameterListNode ...
 func ParseFormalParameterListNode(l *Lexer) (FormalParameterListNode, error) {
-	panic("ParseFormalParameterListNode not implemented")
-	// return nil, nil
+	n := FormalParameterListNode{node: startNode(l)}
+	if !isPunctuator(l.Peek(InputElementDiv), "...") {
+		list, err := ParseFormalsListNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Formals = list.Formals
+		if !isPunctuator(l.Peek(InputElementDiv), ",") {
+			n.finish(l)
+			return n, nil
+		}
+		l.Next(InputElementDiv)
+	}
+	rest, err := ParseFunctionRestParameterNode(l)
+	if err != nil {
+		return n, err
+	}
+	n.Rest = &rest
+	n.finish(l)
+	return n, nil
 }
 
 // FormalsListNode [Yield] : [See 14.1]
@@ -1608,12 +3070,26 @@ func ParseFormalParameterListNode(l *Lexer) (FormalParameterListNode, error) {
 // implements: Parser and ASTNode
 type FormalsListNode struct {
 	node
+	Formals []FormalParameterNode
 }
 
-// ParseFormalsListNode ...
+// ParseFormalsListNode parses parameters up to a comma before a rest
+// parameter, which is left for ParseFormalParameterListNode
 func ParseFormalsListNode(l *Lexer) (FormalsListNode, error) {
-	panic("ParseFormalsListNode not implemented")
-	// return nil, nil
+	n := FormalsListNode{node: startNode(l)}
+	for {
+		formal, err := ParseFormalParameterNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Formals = append(n.Formals, formal)
+		if !isPunctuator(l.Peek(InputElementDiv), ",") || isPunctuator(l.PeekN(2, InputElementDiv), "...") {
+			break
+		}
+		l.Next(InputElementDiv)
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // FunctionRestParameterNode [Yield] : [See 14.1]
@@ -1621,12 +3097,18 @@ func ParseFormalsListNode(l *Lexer) (FormalsListNode, error) {
 // implements: Parser and ASTNode
 type FunctionRestParameterNode struct {
 	node
+	Element BindingRestElementNode
 }
 
 // ParseFunctionRestParameterNode ...
 func ParseFunctionRestParameterNode(l *Lexer) (FunctionRestParameterNode, error) {
-	panic("ParseFunctionRestParameterNode not implemented")
-	// return nil, nil
+	n := FunctionRestParameterNode{node: startNode(l)}
+	var err error
+	if n.Element, err = ParseBindingRestElementNode(l); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // FormalParameterNode [Yield] : [See 14.1]
@@ -1634,12 +3116,18 @@ func ParseFunctionRestParameterNode(l *Lexer) (FunctionRestParameterNode, error)
 // implements: Parser and ASTNode
 type FormalParameterNode struct {
 	node
+	Element BindingElementNode
 }
 
 // ParseFormalParameterNode ...
 func ParseFormalParameterNode(l *Lexer) (FormalParameterNode, error) {
-	panic("ParseFormalParameterNode not implemented")
-	// return nil, nil
+	n := FormalParameterNode{node: startNode(l)}
+	var err error
+	if n.Element, err = ParseBindingElementNode(l); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // FunctionBodyNode [Yield] : [See 14.1]
@@ -1647,12 +3135,63 @@ func ParseFormalParameterNode(l *Lexer) (FormalParameterNode, error) {
 // implements: Parser and ASTNode
 type FunctionBodyNode struct {
 	node
+	child ASTNode
+	// UseStrict is set when the directive prologue of the body has a Use
+	// Strict Directive, which makes the function strict mode code
+	UseStrict bool
 }
 
-// ParseFunctionBodyNode ...
+// ParseFunctionBodyNode parses the statements of a function up to the }
+// that ends it, which is left for the caller
 func ParseFunctionBodyNode(l *Lexer) (FunctionBodyNode, error) {
-	panic("ParseFunctionBodyNode not implemented")
-	// return nil, nil
+	strict, inFunctionBody := l.strict, l.inFunctionBody
+	// the tokens peeked by hasUseStrictDirective are lexed as sloppy mode
+	// code, they are lexed again when it finds a Use Strict Directive as the
+	// directives and the token after one ended by a line terminator may
+	// have an octal escape sequence or be a legacy octal literal
+	cp := l.Mark()
+	useStrict := hasUseStrictDirective(l)
+	if useStrict && !strict {
+		l.Reset(cp)
+		l.setStrict()
+	}
+	l.Release(cp)
+	n := FunctionBodyNode{node: startNode(l), UseStrict: useStrict}
+	l.inFunctionBody = true
+	var err error
+	n.child, err = ParseFunctionStatementListNode(l)
+	if n.UseStrict && !strict {
+		l.unsetStrict()
+	}
+	l.inFunctionBody = inFunctionBody
+	if err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
+}
+
+// hasUseStrictDirective reports whether the directive prologue, the string
+// literal statements at the start of the next statements, has a Use Strict
+// Directive [See 14.1.1]
+func hasUseStrictDirective(l *Lexer) bool {
+	for n := 1; ; {
+		directive := l.PeekN(n, InputElementRegExp)
+		if directive.Type != StringLiteralToken {
+			return false
+		}
+		switch next := l.PeekN(n+1, InputElementDiv); {
+		case isPunctuator(next, ";"):
+			n += 2
+		case isPunctuator(next, "}") || next.Type == EOFToken || next.NewlineBefore:
+			n++
+		default:
+			return false
+		}
+		if directive.Value == `"use strict"` || directive.Value == `'use strict'` {
+			return true
+		}
+	}
 }
 
 // FunctionStatementListNode [Yield] : [See 14.1]
@@ -1660,12 +3199,18 @@ func ParseFunctionBodyNode(l *Lexer) (FunctionBodyNode, error) {
 // implements: Parser and ASTNode
 type FunctionStatementListNode struct {
 	node
+	child ASTNode
 }
 
 // ParseFunctionStatementListNode ...
 func ParseFunctionStatementListNode(l *Lexer) (FunctionStatementListNode, error) {
-	panic("ParseFunctionStatementListNode not implemented")
-	// return nil, nil
+	n := FunctionStatementListNode{node: startNode(l)}
+	var err error
+	if n.child, err = ParseStatementListNode(l); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // ArrowFunctionNode [In, Yield] : [See 14.2]
@@ -1673,12 +3218,41 @@ func ParseFunctionStatementListNode(l *Lexer) (FunctionStatementListNode, error)
 // implements: Parser and ASTNode
 type ArrowFunctionNode struct {
 	node
+	Parameters ArrowParametersNode
+	Body       ConciseBodyNode
 }
 
 // ParseArrowFunctionNode ...
 func ParseArrowFunctionNode(l *Lexer) (ArrowFunctionNode, error) {
-	panic("ParseArrowFunctionNode not implemented")
-	// return nil, nil
+	parameters, err := ParseArrowParametersNode(l)
+	if err != nil {
+		return ArrowFunctionNode{node: startNodeAt(parameters)}, err
+	}
+	return parseArrowFunction(l, parameters, true)
+}
+
+// parseArrowFunction parses the rest of an ArrowFunction after its
+// parameters, in is the [In] grammar parameter
+func parseArrowFunction(l *Lexer, parameters ArrowParametersNode, in bool) (ArrowFunctionNode, error) {
+	n := ArrowFunctionNode{node: startNodeAt(parameters), Parameters: parameters}
+	tok := l.Next(InputElementDiv)
+	if !isPunctuator(tok, "=>") {
+		return n, errors.Errorf("expected '=>' after the parameters of the arrow function %s", tok.FilePosition)
+	}
+	if tok.NewlineBefore {
+		return n, errors.Errorf("a line terminator must not come before '=>' %s", tok.FilePosition)
+	}
+	var err error
+	if n.Body, err = parseConciseBody(l, in); err != nil {
+		return n, err
+	}
+	if body, ok := n.Body.Child.(FunctionBodyNode); ok {
+		if err = checkFunction(l, nil, parameters.FormalParametersNode, body, true); err != nil {
+			return n, err
+		}
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // ArrowParametersNode [Yield] : [See 14.2]
@@ -1689,12 +3263,34 @@ func ParseArrowFunctionNode(l *Lexer) (ArrowFunctionNode, error) {
 // implements: Parser and ASTNode
 type ArrowParametersNode struct {
 	node
+	FormalParametersNode
 }
 
 // ParseArrowParametersNode ...
 func ParseArrowParametersNode(l *Lexer) (ArrowParametersNode, error) {
-	panic("ParseArrowParametersNode not implemented")
-	// return nil, nil
+	n := ArrowParametersNode{node: startNode(l)}
+	if isPunctuator(l.Peek(InputElementRegExp), "(") {
+		cover, err := ParseCoverParenthesizedExpressionAndArrowParameterListNode(l)
+		if err != nil {
+			return n, err
+		}
+		if n.FormalParametersNode, err = cover.arrowParameters(l); err != nil {
+			return n, err
+		}
+	} else {
+		name, err := ParseBindingIdentifierNode(l)
+		if err != nil {
+			return n, err
+		}
+		single := SingleNameBindingNode{node: startNodeAt(name), Name: name}
+		element := BindingElementNode{node: startNodeAt(single), Child: single}
+		n.FormalParametersNode = FormalParametersNode{
+			node:    startNodeAt(element),
+			Formals: []FormalParameterNode{{node: startNodeAt(element), Element: element}},
+		}
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // ConciseBodyNode [In] : [See 14.2]
@@ -1705,12 +3301,33 @@ func ParseArrowParametersNode(l *Lexer) (ArrowParametersNode, error) {
 // implements: Parser and ASTNode
 type ConciseBodyNode struct {
 	node
+	// Child is the AssignmentExpressionNode of a concise body or the
+	// FunctionBodyNode of a body in braces
+	Child ASTNode
 }
 
 // ParseConciseBodyNode ...
 func ParseConciseBodyNode(l *Lexer) (ConciseBodyNode, error) {
-	panic("ParseConciseBodyNode not implemented")
-	// return nil, nil
+	return parseConciseBody(l, true)
+}
+
+// parseConciseBody parses a ConciseBody, in is the [In] grammar parameter
+func parseConciseBody(l *Lexer, in bool) (ConciseBodyNode, error) {
+	n := ConciseBodyNode{node: startNode(l)}
+	var err error
+	if isPunctuator(l.Peek(InputElementRegExp), "{") {
+		l.Next(InputElementRegExp)
+		if n.Child, err = ParseFunctionBodyNode(l); err != nil {
+			return n, err
+		}
+		if tok := l.Next(InputElementDiv); !isPunctuator(tok, "}") {
+			return n, errors.Errorf("expected '}' to end the body of the arrow function %s", tok.FilePosition)
+		}
+	} else if n.Child, err = parseAssignmentExpressionNode(l, in); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // MethodDefinitionNode [Yield] : [See 14.3]
@@ -1721,12 +3338,93 @@ func ParseConciseBodyNode(l *Lexer) (ConciseBodyNode, error) {
 // implements: Parser and ASTNode
 type MethodDefinitionNode struct {
 	node
+	Kind       MethodKind
+	Name       PropertyNameNode
+	Parameters FormalParametersNode
+	Body       FunctionBodyNode
+}
+
+// MethodKind is the kind of a MethodDefinitionNode
+type MethodKind int
+
+// The kinds of MethodDefinitionNode
+const (
+	NormalMethod MethodKind = iota
+	GetMethod
+	SetMethod
+	GeneratorMethod
+)
+
+func (kind MethodKind) String() string {
+	switch kind {
+	case NormalMethod:
+		return "NormalMethod"
+	case GetMethod:
+		return "GetMethod"
+	case SetMethod:
+		return "SetMethod"
+	case GeneratorMethod:
+		return "GeneratorMethod"
+	default:
+		return "UnknownMethod"
+	}
 }
 
 // ParseMethodDefinitionNode ...
 func ParseMethodDefinitionNode(l *Lexer) (MethodDefinitionNode, error) {
-	panic("ParseMethodDefinitionNode not implemented")
-	// return nil, nil
+	n := MethodDefinitionNode{node: startNode(l)}
+	tok := l.Peek(InputElementDiv)
+	switch {
+	case isPunctuator(tok, "*"):
+		n.Kind = GeneratorMethod
+		l.Next(InputElementDiv)
+	case tok.Type == IdentifierNameToken && tok.Value == "get" && !isPunctuator(l.PeekN(2, InputElementDiv), "("):
+		n.Kind = GetMethod
+		l.Next(InputElementDiv)
+	case tok.Type == IdentifierNameToken && tok.Value == "set" && !isPunctuator(l.PeekN(2, InputElementDiv), "("):
+		n.Kind = SetMethod
+		l.Next(InputElementDiv)
+	}
+	var err error
+	if n.Name, err = ParsePropertyNameNode(l); err != nil {
+		return n, err
+	}
+	if tok := l.Next(InputElementDiv); !isPunctuator(tok, "(") {
+		return n, errors.Errorf("expected '(' to start the parameters of the method %s", tok.FilePosition)
+	}
+	switch n.Kind {
+	case GetMethod:
+		n.Parameters.FilePosition = l.CurrentPosition()
+	case SetMethod:
+		parameter, err := ParsePropertySetParameterListNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Parameters = FormalParametersNode{node: parameter.node, Formals: []FormalParameterNode{parameter.Parameter}}
+	default:
+		parameters, err := ParseStrictFormalParametersNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Parameters = parameters.FormalParametersNode
+	}
+	if tok := l.Next(InputElementDiv); !isPunctuator(tok, ")") {
+		return n, errors.Errorf("expected ')' to end the parameters of the method %s", tok.FilePosition)
+	}
+	if tok := l.Next(InputElementDiv); !isPunctuator(tok, "{") {
+		return n, errors.Errorf("expected '{' to start the body of the method %s", tok.FilePosition)
+	}
+	if n.Body, err = ParseFunctionBodyNode(l); err != nil {
+		return n, err
+	}
+	if tok := l.Next(InputElementDiv); !isPunctuator(tok, "}") {
+		return n, errors.Errorf("expected '}' to end the body of the method %s", tok.FilePosition)
+	}
+	if err = checkFunction(l, nil, n.Parameters, n.Body, true); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // PropertySetParameterListNode  : [See 14.3]
@@ -1734,12 +3432,18 @@ func ParseMethodDefinitionNode(l *Lexer) (MethodDefinitionNode, error) {
 // implements: Parser and ASTNode
 type PropertySetParameterListNode struct {
 	node
+	Parameter FormalParameterNode
 }
 
 // ParsePropertySetParameterListNode ...
 func ParsePropertySetParameterListNode(l *Lexer) (PropertySetParameterListNode, error) {
-	panic("ParsePropertySetParameterListNode not implemented")
-	// return nil, nil
+	n := PropertySetParameterListNode{node: startNode(l)}
+	var err error
+	if n.Parameter, err = ParseFormalParameterNode(l); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // GeneratorMethodNode [Yield] : [See 14.4]
@@ -1800,8 +3504,8 @@ type ClassDeclarationNode struct {
 
 // ParseClassDeclarationNode ...
 func ParseClassDeclarationNode(l *Lexer) (ClassDeclarationNode, error) {
-	panic("ParseClassDeclarationNode not implemented")
-	// return nil, nil
+	tok := l.Peek(InputElementRegExp)
+	return ClassDeclarationNode{}, errors.Errorf("class declarations are not supported %s", tok.FilePosition)
 }
 
 // ClassTailNode [Yield] : [See 14.5]
@@ -1886,8 +3590,26 @@ type ScriptNode struct {
 
 // ParseScriptNode ...
 func ParseScriptNode(l *Lexer) (ScriptNode, error) {
+	n := ScriptNode{node: node{FilePosition: l.CurrentPosition(), firstToken: len(l.consumed.tokens)}}
 	c, err := ParseScriptBodyNode(l)
-	return ScriptNode{child: c}, err
+	n.child = c
+	n.finishSource(l)
+	return n, err
+}
+
+// finishSource takes the comments at the end of the input as the trailing
+// comments of a ScriptNode or ModuleNode n
+func (n *node) finishSource(l *Lexer) {
+	if l.Peek(l.goal).Type == EOFToken {
+		n.trailingComments = l.takeLeadingComments()
+	}
+	if l.ConcreteSyntax {
+		// the tokens up to and including EOF are kept, even those that were
+		// not parsed, so that the whole source is printed
+		for l.Next(l.goal).Type != EOFToken {
+		}
+		n.consumed, n.endToken = l.consumed, len(l.consumed.tokens)
+	}
 }
 
 // ScriptBodyNode [See 15.1]
@@ -1900,12 +3622,13 @@ type ScriptBodyNode struct {
 
 // ParseScriptBodyNode ...
 func ParseScriptBodyNode(l *Lexer) (ScriptBodyNode, error) {
-	c, err := ParseStatementListNode(l)
-	node := ScriptBodyNode{
-		child: c,
+	n := ScriptBodyNode{node: startListNode(l)}
+	var err error
+	if n.child, err = ParseStatementListNode(l); err != nil {
+		return n, err
 	}
-	node.FilePosition = l.CurrentPosition()
-	return node, err
+	n.finish(l)
+	return n, nil
 }
 
 // ModuleNode [See 15.2]
@@ -1913,12 +3636,17 @@ func ParseScriptBodyNode(l *Lexer) (ScriptBodyNode, error) {
 // implements: Parser and ASTNode
 type ModuleNode struct {
 	node
+	child ASTNode
 }
 
-// ParseModuleNode ...
+// ParseModuleNode parses module code, l.Module should be set before any
+// token is peeked so that the input is lexed as strict mode code
 func ParseModuleNode(l *Lexer) (ModuleNode, error) {
-	panic("ParseModuleNode not implemented")
-	// return nil, nil
+	n := ModuleNode{node: node{FilePosition: l.CurrentPosition(), firstToken: len(l.consumed.tokens)}}
+	c, err := ParseModuleBodyNode(l)
+	n.child = c
+	n.finishSource(l)
+	return n, err
 }
 
 // ModuleBodyNode [See 15.2]
@@ -1926,12 +3654,18 @@ func ParseModuleNode(l *Lexer) (ModuleNode, error) {
 // implements: Parser and ASTNode
 type ModuleBodyNode struct {
 	node
+	child ASTNode
 }
 
 // ParseModuleBodyNode ...
 func ParseModuleBodyNode(l *Lexer) (ModuleBodyNode, error) {
-	panic("ParseModuleBodyNode not implemented")
-	// return nil, nil
+	n := ModuleBodyNode{node: startListNode(l)}
+	var err error
+	if n.child, err = ParseModuleItemListNode(l); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // ModuleItemListNode [See 15.2]
@@ -1939,13 +3673,24 @@ func ParseModuleBodyNode(l *Lexer) (ModuleBodyNode, error) {
 //  ModuleItemList ModuleItem
 // implements: Parser and ASTNode
 type ModuleItemListNode struct {
+	children []ASTNode
 	node
 }
 
-// ParseModuleItemListNode ...
+// ParseModuleItemListNode parses the items up to the end of the input
 func ParseModuleItemListNode(l *Lexer) (ModuleItemListNode, error) {
-	panic("ParseModuleItemListNode not implemented")
-	// return nil, nil
+	node := ModuleItemListNode{node: startListNode(l)}
+	for {
+		if tok := l.Peek(InputElementRegExp); tok.Type == EOFToken {
+			node.finish(l)
+			return node, nil
+		}
+		child, err := ParseModuleItemNode(l)
+		if err != nil {
+			return node, err
+		}
+		node.children = append(node.children, child)
+	}
 }
 
 // ModuleItemNode [See 15.2]
@@ -1954,13 +3699,27 @@ func ParseModuleItemListNode(l *Lexer) (ModuleItemListNode, error) {
 //  StatementListItem
 // implements: Parser and ASTNode
 type ModuleItemNode struct {
+	child ASTNode
 	node
 }
 
 // ParseModuleItemNode ...
-func ParseModuleItemNode(l *Lexer) (ModuleItemNode, error) {
-	panic("ParseModuleItemNode not implemented")
-	// return nil, nil
+func ParseModuleItemNode(l *Lexer) (node ModuleItemNode, err error) {
+	node.node = startNode(l)
+	defer func() {
+		if err == nil {
+			node.finish(l)
+		}
+	}()
+	switch tok := l.Peek(InputElementRegExp); {
+	case tok.Type == ReservedWordToken && tok.Value == "import":
+		node.child, err = ParseImportDeclarationNode(l)
+	case tok.Type == ReservedWordToken && tok.Value == "export":
+		node.child, err = ParseExportDeclarationNode(l)
+	default:
+		node.child, err = ParseStatementListItemNode(l)
+	}
+	return node, err
 }
 
 // ImportDeclarationNode [See 15.2.2]
@@ -1973,8 +3732,8 @@ type ImportDeclarationNode struct {
 
 // ParseImportDeclarationNode ...
 func ParseImportDeclarationNode(l *Lexer) (ImportDeclarationNode, error) {
-	panic("ParseImportDeclarationNode not implemented")
-	// return nil, nil
+	tok := l.Peek(InputElementRegExp)
+	return ImportDeclarationNode{}, errors.Errorf("import declarations are not supported %s", tok.FilePosition)
 }
 
 // ImportClauseNode [See 15.2.2]
@@ -2080,12 +3839,41 @@ func ParseImportedBindingNode(l *Lexer) (ImportedBindingNode, error) {
 // implements: Parser and ASTNode
 type ExportDeclarationNode struct {
 	node
+	// Default is set for an export default, the Declaration is then a
+	// HoistableDeclarationNode, a ClassDeclarationNode or an
+	// AssignmentExpressionNode
+	Default     bool
+	Declaration ASTNode
 }
 
 // ParseExportDeclarationNode ...
 func ParseExportDeclarationNode(l *Lexer) (ExportDeclarationNode, error) {
-	panic("ParseExportDeclarationNode not implemented")
-	// return nil, nil
+	n := ExportDeclarationNode{node: startNode(l)}
+	if tok := l.Peek(InputElementRegExp); tok.Type != ReservedWordToken || tok.Value != "export" {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementRegExp)
+	if tok := l.Peek(InputElementRegExp); tok.Type != ReservedWordToken || tok.Value != "default" {
+		return n, errors.Errorf("only export default declarations are supported %s", tok.FilePosition)
+	}
+	l.Next(InputElementRegExp)
+	n.Default = true
+	var err error
+	switch tok := l.Peek(InputElementRegExp); {
+	case tok.Type == ReservedWordToken && tok.Value == "function":
+		n.Declaration, err = parseHoistableDeclaration(l, true)
+	case tok.Type == ReservedWordToken && tok.Value == "class":
+		n.Declaration, err = ParseClassDeclarationNode(l)
+	default:
+		if n.Declaration, err = parseAssignmentExpressionNode(l, true); err == nil {
+			err = parseSemicolon(l)
+		}
+	}
+	if err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // ExportClauseNode [See 15.2.3]
@@ -2114,7 +3902,7 @@ type ExportsListNode struct {
 
 // ParseExportsListNode ...
 func ParseExportsListNode(l *Lexer) (ExportsListNode, error) {
-	n := ExportsListNode{node: node{l.CurrentPosition()}}
+	n := ExportsListNode{node: startNode(l)}
 
 	n.List = []ExportSpecifierNode{}
 
@@ -2126,6 +3914,7 @@ func ParseExportsListNode(l *Lexer) (ExportsListNode, error) {
 		n.List = append(n.List, exportSpecifier)
 
 		if comma := l.Peek(l.goal); comma.Value != "," {
+			n.finish(l)
 			return n, err
 		}
 		l.Next(l.goal)
@@ -2144,7 +3933,7 @@ type ExportSpecifierNode struct {
 
 // ParseExportSpecifierNode ...
 func ParseExportSpecifierNode(l *Lexer) (ExportSpecifierNode, error) {
-	n := ExportSpecifierNode{node: node{l.CurrentPosition()}}
+	n := ExportSpecifierNode{node: startNode(l)}
 
 	identifierNode, err := ParseIdentifierNode(l)
 	if err != nil {
@@ -2153,6 +3942,7 @@ func ParseExportSpecifierNode(l *Lexer) (ExportSpecifierNode, error) {
 	n.IdentifierNode = identifierNode
 
 	if as := l.Peek(l.goal); as.Value != "as" {
+		n.node.finish(l)
 		return n, nil
 	}
 	l.Next(l.goal)
@@ -2160,5 +3950,6 @@ func ParseExportSpecifierNode(l *Lexer) (ExportSpecifierNode, error) {
 	identifierNode, err = ParseIdentifierNode(l)
 	n.As = identifierNode
 
+	n.node.finish(l)
 	return n, err
 }
@@ -1,5 +1,11 @@
 package es6
 
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
 // PrimaryExpressionNode [Yield] : [See 12.2]
 //  this
 //  IdentifierReference[?Yield]
@@ -17,12 +23,80 @@ package es6
 // implements: Parser and ASTNode
 type PrimaryExpressionNode struct {
 	node
+	// Child is the node of the alternative that was parsed, such as a
+	// ThisNode, an IdentifierReferenceNode or a LiteralNode
+	Child ASTNode
 }
 
 // ParsePrimaryExpressionNode ...
 func ParsePrimaryExpressionNode(l *Lexer) (PrimaryExpressionNode, error) {
-	panic("ParsePrimaryExpressionNode not implemented")
-	// return nil, nil
+	n := PrimaryExpressionNode{node: startNode(l)}
+	var err error
+	switch tok := l.Peek(InputElementRegExp); tok.Type {
+	case ReservedWordToken:
+		switch tok.Value {
+		case "this":
+			n.Child, err = ParseThisNode(l)
+		case "null", "true", "false":
+			n.Child, err = ParseLiteralNode(l)
+		case "function":
+			if next := l.PeekN(2, InputElementDiv); next.Type == PunctuatorToken && next.Value == "*" {
+				n.Child, err = ParseGeneratorExpressionNode(l)
+			} else {
+				n.Child, err = ParseFunctionExpressionNode(l)
+			}
+		case "class":
+			n.Child, err = ParseClassExpressionNode(l)
+		default:
+			err = IncorrectTokenError(tok)
+		}
+	case IdentifierNameToken:
+		n.Child, err = ParseIdentifierReferenceNode(l)
+	case NumericLiteralToken, StringLiteralToken:
+		n.Child, err = ParseLiteralNode(l)
+	case RegExToken:
+		n.Child, err = ParseRegularExpressionLiteralNode(l)
+	case NoSubstitutionTemplateToken, TemplateHeadToken:
+		n.Child, err = ParseTemplateLiteralNode(l)
+	case PunctuatorToken:
+		switch tok.Value {
+		case "[":
+			n.Child, err = ParseArrayLiteralNode(l)
+		case "{":
+			n.Child, err = ParseObjectLiteralNode(l)
+		case "(":
+			n.Child, err = ParseParenthesizedExpressionNode(l)
+		default:
+			err = IncorrectTokenError(tok)
+		}
+	case ErrorToken:
+		err = errors.Errorf("%s %s", tok.Value, tok.FilePosition)
+	default:
+		err = IncorrectTokenError(tok)
+	}
+	if err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
+}
+
+// ThisNode  : [See 12.2.2]
+//  this
+// implements: Parser and ASTNode
+type ThisNode struct {
+	node
+}
+
+// ParseThisNode ...
+func ParseThisNode(l *Lexer) (ThisNode, error) {
+	n := ThisNode{node: startNode(l)}
+	if tok := l.Peek(InputElementRegExp); tok.Type != ReservedWordToken || tok.Value != "this" {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementRegExp)
+	n.finish(l)
+	return n, nil
 }
 
 // LabelIdentifierNode [Yield] : [See 12.1]
@@ -31,12 +105,45 @@ func ParsePrimaryExpressionNode(l *Lexer) (PrimaryExpressionNode, error) {
 // implements: Parser and ASTNode
 type LabelIdentifierNode struct {
 	node
+	Name string
 }
 
 // ParseLabelIdentifierNode ...
 func ParseLabelIdentifierNode(l *Lexer) (LabelIdentifierNode, error) {
-	panic("ParseLabelIdentifierNode not implemented")
-	// return nil, nil
+	n := LabelIdentifierNode{node: startNode(l)}
+	tok, err := parseIdentifier(l)
+	if err != nil {
+		return n, err
+	}
+	n.Name = tok.Cooked
+	n.finish(l)
+	return n, nil
+}
+
+// LiteralKind is the kind of value of a LiteralNode
+type LiteralKind int
+
+// The kinds of LiteralNode
+const (
+	NullLiteral LiteralKind = iota
+	BooleanLiteral
+	NumericLiteral
+	StringLiteral
+)
+
+func (kind LiteralKind) String() string {
+	switch kind {
+	case NullLiteral:
+		return "NullLiteral"
+	case BooleanLiteral:
+		return "BooleanLiteral"
+	case NumericLiteral:
+		return "NumericLiteral"
+	case StringLiteral:
+		return "StringLiteral"
+	default:
+		return "UnknownLiteral"
+	}
 }
 
 // LiteralNode  : [See 12.2.4]
@@ -47,12 +154,60 @@ func ParseLabelIdentifierNode(l *Lexer) (LabelIdentifierNode, error) {
 // implements: Parser and ASTNode
 type LiteralNode struct {
 	node
+	Kind LiteralKind
+	// Boolean is the value of a BooleanLiteral
+	Boolean bool
+	// Number is the value of a NumericLiteral, BigInt is set instead for a
+	// NumericLiteral with the BigInt suffix n
+	Number float64
+	BigInt *big.Int
+	// Cooked is the value of a StringLiteral with its escape sequences
+	// decoded
+	Cooked string
 }
 
 // ParseLiteralNode ...
 func ParseLiteralNode(l *Lexer) (LiteralNode, error) {
-	panic("ParseLiteralNode not implemented")
-	// return nil, nil
+	n := LiteralNode{node: startNode(l)}
+	switch tok := l.Peek(InputElementRegExp); {
+	case tok.Type == ReservedWordToken && tok.Value == "null":
+		n.Kind = NullLiteral
+	case tok.Type == ReservedWordToken && (tok.Value == "true" || tok.Value == "false"):
+		n.Kind = BooleanLiteral
+		n.Boolean = tok.Value == "true"
+	case tok.Type == NumericLiteralToken:
+		n.Kind = NumericLiteral
+		n.Number, n.BigInt = tok.Number, tok.BigInt
+	case tok.Type == StringLiteralToken:
+		n.Kind = StringLiteral
+		n.Cooked = tok.Cooked
+	default:
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementRegExp)
+	n.finish(l)
+	return n, nil
+}
+
+// RegularExpressionLiteralNode  : [See 12.2.8]
+//  RegularExpressionLiteral
+// implements: Parser and ASTNode
+type RegularExpressionLiteralNode struct {
+	node
+	Pattern, Flags string
+}
+
+// ParseRegularExpressionLiteralNode ...
+func ParseRegularExpressionLiteralNode(l *Lexer) (RegularExpressionLiteralNode, error) {
+	n := RegularExpressionLiteralNode{node: startNode(l)}
+	tok := l.Peek(InputElementRegExp)
+	if tok.Type != RegExToken {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementRegExp)
+	n.Pattern, n.Flags = tok.Pattern, tok.Flags
+	n.finish(l)
+	return n, nil
 }
 
 // ArrayLiteralNode [Yield] : [See 12.2.5]
@@ -62,12 +217,53 @@ func ParseLiteralNode(l *Lexer) (LiteralNode, error) {
 // implements: Parser and ASTNode
 type ArrayLiteralNode struct {
 	node
+	// Elements are the AssignmentExpressionNode, SpreadElementNode or
+	// HoleNode of each element of the array
+	Elements      []ASTNode
+	trailingComma bool // the ElementList is followed by a comma
 }
 
 // ParseArrayLiteralNode ...
 func ParseArrayLiteralNode(l *Lexer) (ArrayLiteralNode, error) {
-	panic("ParseArrayLiteralNode not implemented")
-	// return nil, nil
+	n := ArrayLiteralNode{node: startNode(l)}
+	if tok := l.Peek(InputElementRegExp); !isPunctuator(tok, "[") {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementRegExp)
+	if !isElisionBefore(l, 1, "]") {
+		list, err := ParseElementListNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Elements = list.Elements
+		if isPunctuator(l.Peek(InputElementDiv), ",") {
+			l.Next(InputElementDiv)
+			n.trailingComma = true
+		}
+	}
+	if isPunctuator(l.Peek(InputElementDiv), ",") {
+		elision, err := ParseElisionNode(l)
+		if err != nil {
+			return n, err
+		}
+		for _, hole := range elision.Holes {
+			n.Elements = append(n.Elements, hole)
+		}
+	}
+	if tok := l.Next(InputElementDiv); !isPunctuator(tok, "]") {
+		return n, errors.Errorf("expected ']' to end the array literal %s", tok.FilePosition)
+	}
+	n.finish(l)
+	return n, nil
+}
+
+// isElisionBefore reports whether the tokens from the nth next token are
+// zero or more commas followed by the punctuator value
+func isElisionBefore(l *Lexer, n int, value string) bool {
+	for isPunctuator(l.PeekN(n, InputElementDiv), ",") {
+		n++
+	}
+	return isPunctuator(l.PeekN(n, InputElementDiv), value)
 }
 
 // ObjectLiteralNode [Yield] : [See 12.2.6]
@@ -77,12 +273,31 @@ func ParseArrayLiteralNode(l *Lexer) (ArrayLiteralNode, error) {
 // implements: Parser and ASTNode
 type ObjectLiteralNode struct {
 	node
+	Properties []PropertyDefinitionNode
 }
 
 // ParseObjectLiteralNode ...
 func ParseObjectLiteralNode(l *Lexer) (ObjectLiteralNode, error) {
-	panic("ParseObjectLiteralNode not implemented")
-	// return nil, nil
+	n := ObjectLiteralNode{node: startNode(l)}
+	if tok := l.Peek(InputElementRegExp); !isPunctuator(tok, "{") {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementRegExp)
+	if !isPunctuator(l.Peek(InputElementDiv), "}") {
+		list, err := ParsePropertyDefinitionListNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Properties = list.Properties
+		if isPunctuator(l.Peek(InputElementDiv), ",") {
+			l.Next(InputElementDiv)
+		}
+	}
+	if tok := l.Next(InputElementDiv); !isPunctuator(tok, "}") {
+		return n, errors.Errorf("expected '}' to end the object literal %s", tok.FilePosition)
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // FunctionExpressionNode  : [See 14.1]
@@ -90,12 +305,33 @@ func ParseObjectLiteralNode(l *Lexer) (ObjectLiteralNode, error) {
 // implements: Parser and ASTNode
 type FunctionExpressionNode struct {
 	node
+	// BindingIdentifier is nil for an anonymous function
+	BindingIdentifier *BindingIdentifierNode
+	FormalParameters  FormalParametersNode
+	FunctionBody      FunctionBodyNode
 }
 
 // ParseFunctionExpressionNode ...
 func ParseFunctionExpressionNode(l *Lexer) (FunctionExpressionNode, error) {
-	panic("ParseFunctionExpressionNode not implemented")
-	// return nil, nil
+	n := FunctionExpressionNode{node: startNode(l)}
+	tok := l.Peek(InputElementRegExp)
+	if tok.Type != ReservedWordToken || tok.Value != "function" || isPunctuator(l.PeekN(2, InputElementDiv), "*") {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementRegExp)
+	if !isPunctuator(l.Peek(InputElementDiv), "(") {
+		name, err := parseFunctionName(l)
+		if err != nil {
+			return n, err
+		}
+		n.BindingIdentifier = &name
+	}
+	var err error
+	if n.FormalParameters, n.FunctionBody, err = parseFunctionParametersAndBody(l, n.BindingIdentifier); err != nil {
+		return n, err
+	}
+	n.finish(l)
+	return n, nil
 }
 
 // ClassExpressionNode [Yield] : [See 14.5]
@@ -120,6 +356,11 @@ func ParseClassExpressionNode(l *Lexer) (ClassExpressionNode, error) {
 // implements: Parser and ASTNode
 type CoverParenthesizedExpressionAndArrowParameterListNode struct {
 	node
+	// Expression is nil when the parentheses are empty or only have the
+	// rest parameter Rest
+	Expression           *ExpressionNode
+	Rest                 *BindingRestElementNode
+	coverInitializedName *FilePosition // the first CoverInitializedName in the Expression
 }
 
 // GeneratorExpressionNode  : [See 14.4]
@@ -148,10 +389,49 @@ type GeneratorBodyNode struct {
 // implements: Parser and ASTNode
 type TemplateLiteralNode struct {
 	node
+	// Cooked and Raw are the values of the strings around the substitutions,
+	// there is one more of each than there are Substitutions
+	Cooked, Raw   []string
+	Substitutions []ExpressionNode
+	// CookedErrs has the error of each string with an invalid escape
+	// sequence, which is only allowed in a tagged template. The Cooked value
+	// of such a string is undefined
+	CookedErrs []error
 }
 
 // ParseTemplateLiteralNode ...
 func ParseTemplateLiteralNode(l *Lexer) (TemplateLiteralNode, error) {
-	panic("ParseTemplateLiteralNode not implemented")
-	// return nil, nil
+	return parseTemplateLiteral(l, false)
+}
+
+// parseTemplateLiteral parses a TemplateLiteral, invalid escape sequences
+// are only reported when it is not tagged
+func parseTemplateLiteral(l *Lexer, tagged bool) (TemplateLiteralNode, error) {
+	n := TemplateLiteralNode{node: startNode(l)}
+	tok := l.Peek(InputElementRegExp)
+	if tok.Type != NoSubstitutionTemplateToken && tok.Type != TemplateHeadToken {
+		return n, IncorrectTokenError(tok)
+	}
+	l.Next(InputElementRegExp)
+	for {
+		if tok.CookedErr != nil && !tagged {
+			return n, errors.Wrapf(tok.CookedErr, "invalid template literal %s", tok.FilePosition)
+		}
+		n.Cooked = append(n.Cooked, tok.Cooked)
+		n.CookedErrs = append(n.CookedErrs, tok.CookedErr)
+		n.Raw = append(n.Raw, tok.Raw)
+		if tok.Type == NoSubstitutionTemplateToken || tok.Type == TemplateTailToken {
+			break
+		}
+		substitution, err := ParseExpressionNode(l)
+		if err != nil {
+			return n, err
+		}
+		n.Substitutions = append(n.Substitutions, substitution)
+		if tok = l.Next(InputElementTemplateTail); tok.Type != TemplateMiddleToken && tok.Type != TemplateTailToken {
+			return n, errors.Errorf("expected '}' to end the template substitution %s", tok.FilePosition)
+		}
+	}
+	n.finish(l)
+	return n, nil
 }
@@ -0,0 +1,102 @@
+package es6
+
+import "testing"
+
+func TestParseExpression_In(t *testing.T) {
+	t.Run("should stop before in without the In parameter", func(t *testing.T) {
+		l := Lex("", "a in b", false)
+		n, err := parseExpression(l, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(n.Expressions) != 1 {
+			t.Fatalf("expected 1 expression but got %d", len(n.Expressions))
+		}
+		if _, ok := n.Expressions[0].Child.(PrimaryExpressionNode); !ok {
+			t.Errorf("expected the identifier a but got %T", n.Expressions[0].Child)
+		}
+		if tok := l.Peek(InputElementDiv); tok.Type != ReservedWordToken || tok.Value != "in" {
+			t.Errorf("expected in to be the next token but got %s", tok)
+		}
+	})
+
+	t.Run("should stop before in after an assignment and a conditional", func(t *testing.T) {
+		l := Lex("", "x = a ? b in c : d in e", false)
+		if _, err := parseExpression(l, false); err != nil {
+			t.Fatal(err)
+		}
+		if tok := l.Peek(InputElementDiv); tok.Type != ReservedWordToken || tok.Value != "in" {
+			t.Errorf("expected in to be the next token but got %s", tok)
+		}
+		if tok := l.PeekN(2, InputElementDiv); tok.Value != "e" {
+			t.Errorf("expected e to follow in but got %s", tok)
+		}
+	})
+
+	t.Run("should allow in inside brackets", func(t *testing.T) {
+		l := Lex("", "(a in b) + [c in d] + f(e in g)", false)
+		if _, err := parseExpression(l, false); err != nil {
+			t.Fatal(err)
+		}
+		if tok := l.Peek(InputElementDiv); tok.Type != EOFToken {
+			t.Errorf("expected the whole input to be parsed but got %s", tok)
+		}
+	})
+}
+
+func TestParseScriptNode_StatementComments(t *testing.T) {
+	l := Lex("", "/* license */ a;\n/** doc */\nb; // after b\n", false)
+	script, err := ParseScriptNode(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if script.Line != 1 || script.Column != 0 || len(script.LeadingComments()) != 0 {
+		t.Errorf("expected the script to start at 1:0 without comments but got %d:%d with %v", script.Line, script.Column, script.LeadingComments())
+	}
+	body := script.child.(ScriptBodyNode).child.(StatementListNode)
+	if len(body.children) != 2 {
+		t.Fatalf("expected 2 statements but got %d", len(body.children))
+	}
+	for i, tc := range []struct {
+		leading  string
+		line     int
+		column   int
+		trailing string
+	}{
+		{leading: "/* license */", line: 1, column: 14},
+		{leading: "/** doc */", line: 3, column: 0, trailing: "// after b"},
+	} {
+		statement := body.children[i].(StatementListItemNode)
+		if comments := statement.LeadingComments(); len(comments) != 1 || comments[0].Source != tc.leading {
+			t.Errorf("expected statement %d to have the leading comment %s but got %v", i, tc.leading, comments)
+		}
+		if statement.Line != tc.line || statement.Column != tc.column {
+			t.Errorf("expected statement %d at %d:%d but got %d:%d", i, tc.line, tc.column, statement.Line, statement.Column)
+		}
+		trailing := statement.child.(StatementNode).child.(ExpressionStatementNode).TrailingComments()
+		if tc.trailing != "" && (len(trailing) != 1 || trailing[0].Source != tc.trailing) {
+			t.Errorf("expected statement %d to have the trailing comment %s but got %v", i, tc.trailing, trailing)
+		}
+	}
+}
+
+func TestLexer_ResetParserState(t *testing.T) {
+	l := Lex("", "({a = 1})", false)
+	cp := l.Mark()
+	l.Next(InputElementRegExp)
+	if _, err := parseAssignmentExpression(l, true); err != nil {
+		t.Fatal(err)
+	}
+	if l.coverInitializedName == nil {
+		t.Fatal("expected the CoverInitializedName to be pending")
+	}
+	l.setStrict()
+	l.inFunctionBody = true
+	l.Reset(cp)
+	if l.coverInitializedName != nil || l.strict || l.inFunctionBody {
+		t.Errorf("expected the parser state to be reset but got %v, strict %t and in a function body %t", l.coverInitializedName, l.strict, l.inFunctionBody)
+	}
+	if _, err := ParseExpressionNode(l); err == nil {
+		t.Errorf("expected an error for the CoverInitializedName after the reset")
+	}
+}
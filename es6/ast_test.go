@@ -1,6 +1,9 @@
 package es6_test
 
 import (
+	"fmt"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/crhntr/gobel/es6"
@@ -201,3 +204,819 @@ func TestParseContinueStatementNode(t *testing.T) {
 		}
 	})
 }
+
+func TestNodeComments(t *testing.T) {
+	js := "/** docs */ foo, bar // trailing\n"
+	lex := es6.Lex("", js, false)
+	node, err := es6.ParseExportsListNode(lex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(node.List) != 2 {
+		t.Fatalf("expected 2 export specifiers but got %d", len(node.List))
+	}
+
+	var _ es6.ASTNode = node
+	if leading := node.LeadingComments(); len(leading) != 1 || leading[0].Value != "* docs " {
+		t.Errorf("expected the doc comment to lead the list but got %v", leading)
+	}
+	if leading := node.List[0].LeadingComments(); len(leading) != 0 {
+		t.Errorf("the doc comment should only be attached once but got %v", leading)
+	}
+	if trailing := node.List[1].IdentifierNode.TrailingComments(); len(trailing) != 1 || trailing[0].Value != " trailing" {
+		t.Errorf("expected the comment to trail bar but got %v", trailing)
+	}
+	if trailing := node.TrailingComments(); len(trailing) != 0 {
+		t.Errorf("the trailing comment should only be attached once but got %v", trailing)
+	}
+}
+
+func TestPrint_ConcreteSyntax(t *testing.T) {
+	for _, row := range []struct {
+		js    string
+		parse func(l *es6.Lexer) (es6.ASTNode, error)
+	}{
+		{"\t// comment\r\n  foo", func(l *es6.Lexer) (es6.ASTNode, error) { return es6.ParseIdentifierNode(l) }},
+		{"/** docs */ foo ,\n/* b */bar", func(l *es6.Lexer) (es6.ASTNode, error) { return es6.ParseExportsListNode(l) }},
+		{"continue /* x */ label", func(l *es6.Lexer) (es6.ASTNode, error) { return es6.ParseContinueStatementNode(l) }},
+	} {
+		lex := es6.Lex("", row.js, false)
+		lex.ConcreteSyntax = true
+		node, err := row.parse(lex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b strings.Builder
+		if err := es6.Print(&b, node); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != row.js {
+			t.Errorf("expected %q to be printed but got %q", row.js, b.String())
+		}
+	}
+}
+
+func TestPrint_ConcreteSyntaxReset(t *testing.T) {
+	js := " foo"
+	lex := es6.Lex("", js, false)
+	lex.ConcreteSyntax = true
+	cp := lex.Mark()
+	lex.Next(es6.InputElementDiv)
+	lex.Reset(cp)
+	node, err := es6.ParseIdentifierNode(lex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokens := node.Tokens(); len(tokens) != 2 {
+		t.Errorf("expected the whitespace and foo but got %v", tokens)
+	}
+
+	lex = es6.Lex("", js, false)
+	if node, _ := es6.ParseIdentifierNode(lex); node.Tokens() != nil {
+		t.Errorf("expected no tokens without ConcreteSyntax but got %v", node.Tokens())
+	}
+}
+
+func TestPrint_ConcreteSyntaxLongChain(t *testing.T) {
+	js := "x = a" + strings.Repeat(" + a", 4000) + ";"
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	lex := es6.Lex("", js, false)
+	lex.ConcreteSyntax = true
+	node, err := es6.ParseScriptNode(lex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runtime.ReadMemStats(&after)
+	// the nodes share the consumed tokens so memory grows with the number
+	// of tokens and not with the depth of the nodes that span them
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 64<<20 {
+		t.Errorf("expected parsing to allocate less than 64 MB but it allocated %d MB", allocated>>20)
+	}
+	var b strings.Builder
+	if err := es6.Print(&b, node); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != js {
+		t.Errorf("expected the source to be printed unchanged")
+	}
+}
+
+func TestParsePrimaryExpressionNode(t *testing.T) {
+	t.Run("should parse this", func(t *testing.T) {
+		node, err := es6.ParsePrimaryExpressionNode(es6.Lex("", "this", false))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := node.Child.(es6.ThisNode); !ok {
+			t.Errorf("node.Child should be a ThisNode but got %T", node.Child)
+		}
+	})
+
+	t.Run("should parse an IdentifierReference", func(t *testing.T) {
+		node, err := es6.ParsePrimaryExpressionNode(es6.Lex("", `fo\u{6F}`, false))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ref, ok := node.Child.(es6.IdentifierReferenceNode)
+		if !ok {
+			t.Fatalf("node.Child should be an IdentifierReferenceNode but got %T", node.Child)
+		}
+		if ref.Name != "foo" {
+			t.Errorf("ref.Name should be %q but got %q", "foo", ref.Name)
+		}
+	})
+
+	t.Run("should parse a RegularExpressionLiteral", func(t *testing.T) {
+		node, err := es6.ParsePrimaryExpressionNode(es6.Lex("", `/a+b/gi`, false))
+		if err != nil {
+			t.Fatal(err)
+		}
+		re, ok := node.Child.(es6.RegularExpressionLiteralNode)
+		if !ok {
+			t.Fatalf("node.Child should be a RegularExpressionLiteralNode but got %T", node.Child)
+		}
+		if re.Pattern != "a+b" || re.Flags != "gi" {
+			t.Errorf("expected pattern %q and flags %q but got %q and %q", "a+b", "gi", re.Pattern, re.Flags)
+		}
+	})
+
+	t.Run("should parse a TemplateLiteral", func(t *testing.T) {
+		node, err := es6.ParsePrimaryExpressionNode(es6.Lex("", "`a${b}c${`d`}\\x65`", false))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tmpl, ok := node.Child.(es6.TemplateLiteralNode)
+		if !ok {
+			t.Fatalf("node.Child should be a TemplateLiteralNode but got %T", node.Child)
+		}
+		cooked := []string{"a", "c", "e"}
+		raw := []string{"a", "c", `\x65`}
+		if len(tmpl.Cooked) != len(cooked) || len(tmpl.Raw) != len(raw) || len(tmpl.Substitutions) != 2 {
+			t.Fatalf("expected %d strings and 2 substitutions but got %q, %q and %d", len(cooked), tmpl.Cooked, tmpl.Raw, len(tmpl.Substitutions))
+		}
+		for i := range cooked {
+			if tmpl.Cooked[i] != cooked[i] || tmpl.Raw[i] != raw[i] {
+				t.Errorf("string %d should be %q (raw %q) but got %q (raw %q)", i, cooked[i], raw[i], tmpl.Cooked[i], tmpl.Raw[i])
+			}
+		}
+	})
+
+	for _, tc := range []struct {
+		name, input string
+		strict      bool
+	}{
+		{name: "a keyword", input: "if"},
+		{name: "an escaped reserved word", input: `\u0069f`},
+		{name: "yield in strict mode code", input: "yield", strict: true},
+		{name: "an invalid escape in a template", input: "`\\unicode`"},
+		{name: "an unterminated substitution", input: "`a${b`"},
+	} {
+		t.Run("should not allow "+tc.name, func(t *testing.T) {
+			if _, err := es6.ParsePrimaryExpressionNode(es6.Lex("", tc.input, tc.strict)); err == nil {
+				t.Errorf("expected an error for %q", tc.input)
+			}
+		})
+	}
+}
+
+func TestParseLiteralNode(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  es6.LiteralNode
+	}{
+		{input: "null", want: es6.LiteralNode{Kind: es6.NullLiteral}},
+		{input: "true", want: es6.LiteralNode{Kind: es6.BooleanLiteral, Boolean: true}},
+		{input: "false", want: es6.LiteralNode{Kind: es6.BooleanLiteral}},
+		{input: "0x1F", want: es6.LiteralNode{Kind: es6.NumericLiteral, Number: 31}},
+		{input: "1.5e1", want: es6.LiteralNode{Kind: es6.NumericLiteral, Number: 15}},
+		{input: `'a\tb'`, want: es6.LiteralNode{Kind: es6.StringLiteral, Cooked: "a\tb"}},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			node, err := es6.ParseLiteralNode(es6.Lex("", tc.input, false))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if node.Kind != tc.want.Kind || node.Boolean != tc.want.Boolean ||
+				node.Number != tc.want.Number || node.Cooked != tc.want.Cooked {
+				t.Errorf("expected a %s with %v, %v and %q but got a %s with %v, %v and %q",
+					tc.want.Kind, tc.want.Boolean, tc.want.Number, tc.want.Cooked,
+					node.Kind, node.Boolean, node.Number, node.Cooked)
+			}
+		})
+	}
+
+	t.Run("should parse a BigInt", func(t *testing.T) {
+		node, err := es6.ParseLiteralNode(es6.Lex("", "12345678901234567890n", false))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if node.BigInt == nil || node.BigInt.String() != "12345678901234567890" {
+			t.Errorf("expected the BigInt 12345678901234567890 but got %v", node.BigInt)
+		}
+	})
+
+	t.Run("should not allow an identifier", func(t *testing.T) {
+		if _, err := es6.ParseLiteralNode(es6.Lex("", "foo", false)); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestParseLabelIdentifierNode(t *testing.T) {
+	node, err := es6.ParseLabelIdentifierNode(es6.Lex("", "outer", false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.Name != "outer" {
+		t.Errorf("node.Name should be %q but got %q", "outer", node.Name)
+	}
+	if _, err := es6.ParseLabelIdentifierNode(es6.Lex("", "let", true)); err == nil {
+		t.Error("let should not be a LabelIdentifier in strict mode code")
+	}
+}
+
+func TestParseArrayLiteralNode(t *testing.T) {
+	kind := func(n es6.ASTNode) string {
+		switch n.(type) {
+		case es6.HoleNode:
+			return "hole"
+		case es6.SpreadElementNode:
+			return "spread"
+		case es6.AssignmentExpressionNode:
+			return "expression"
+		default:
+			return "unknown"
+		}
+	}
+	for _, tc := range []struct {
+		input string
+		want  []string
+	}{
+		{input: "[]"},
+		{input: "[,]", want: []string{"hole"}},
+		{input: "[,,]", want: []string{"hole", "hole"}},
+		{input: "[a,]", want: []string{"expression"}},
+		{input: "[a,,]", want: []string{"expression", "hole"}},
+		{input: "[,a,,b,...c]", want: []string{"hole", "expression", "hole", "expression", "spread"}},
+		{input: "[...a, ...b,]", want: []string{"spread", "spread"}},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			node, err := es6.ParseArrayLiteralNode(es6.Lex("", tc.input, false))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, element := range node.Elements {
+				got = append(got, kind(element))
+			}
+			if strings.Join(got, " ") != strings.Join(tc.want, " ") {
+				t.Errorf("expected elements %q but got %q", tc.want, got)
+			}
+		})
+	}
+
+	t.Run("should not allow a missing ]", func(t *testing.T) {
+		if _, err := es6.ParseArrayLiteralNode(es6.Lex("", "[a b]", false)); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestParseObjectLiteralNode(t *testing.T) {
+	src := "{a, b: 1, [c]: 2, 'd': 3, 4: e, if: f, get g() {}, set g(v) {}, *h() { h; }, i(j, ...k) {}, get: 1, set() {},}"
+	node, err := es6.ParseObjectLiteralNode(es6.Lex("", src, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	name := func(n es6.PropertyNameNode) string {
+		if literal, ok := n.Child.(es6.LiteralPropertyNameNode); ok {
+			return literal.Name
+		}
+		return "[computed]"
+	}
+	var got []string
+	for _, property := range node.Properties {
+		switch child := property.Child.(type) {
+		case es6.IdentifierReferenceNode:
+			got = append(got, "shorthand "+child.Name)
+		case es6.PropertyNameNode:
+			got = append(got, "property "+name(child))
+		case es6.MethodDefinitionNode:
+			got = append(got, fmt.Sprintf("%s %s %d", child.Kind, name(child.Name), len(child.Parameters.Formals)))
+		default:
+			got = append(got, fmt.Sprintf("%T", child))
+		}
+	}
+	want := []string{
+		"shorthand a", "property b", "property [computed]", "property d", "property 4", "property if",
+		"GetMethod g 0", "SetMethod g 1", "GeneratorMethod h 0", "NormalMethod i 1", "property get", "NormalMethod set 0",
+	}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("expected properties\n%q\nbut got\n%q", want, got)
+	}
+
+	for _, tc := range []struct{ input, want string }{
+		{input: "{1e21: 0}", want: "1e+21"},
+		{input: "{0x10: 0}", want: "16"},
+		{input: "{.5: 0}", want: "0.5"},
+		{input: "{1e-7: 0}", want: "1e-7"},
+		{input: "{123456789012345680000: 0}", want: "123456789012345680000"},
+		{input: `{'\x61': 0}`, want: "a"},
+	} {
+		t.Run("should name the property "+tc.want, func(t *testing.T) {
+			node, err := es6.ParseObjectLiteralNode(es6.Lex("", tc.input, false))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := name(node.Properties[0].Child.(es6.PropertyNameNode)); got != tc.want {
+				t.Errorf("expected %q but got %q", tc.want, got)
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		name, input string
+		strict      bool
+	}{
+		{name: "a missing value", input: "{a: }"},
+		{name: "duplicate method parameters", input: "{m(a, a) {}}"},
+		{name: "a getter with parameters", input: "{get g(a) {}}"},
+		{name: "a setter without a parameter", input: "{set s() {}}"},
+		{name: "binding eval in strict mode code", input: "{m(eval) {}}", strict: true},
+	} {
+		t.Run("should not allow "+tc.name, func(t *testing.T) {
+			if _, err := es6.ParseObjectLiteralNode(es6.Lex("", tc.input, tc.strict)); err == nil {
+				t.Errorf("expected an error for %q", tc.input)
+			}
+		})
+	}
+}
+
+func TestParseAssignmentExpressionNode_AssignmentPattern(t *testing.T) {
+	for _, src := range []string{
+		"a = 1",
+		"a += 1",
+		"{a = 1} = b",
+		"{a, b: c = 1, [d]: [e]} = f",
+		"[{a = 1}] = b",
+		"{b: {a = 1}} = c",
+		"[a, , ...[b, c]] = d",
+		"[(a)] = b",
+		"a = {b = 1} = c",
+	} {
+		t.Run(src, func(t *testing.T) {
+			if _, err := es6.ParseAssignmentExpressionNode(es6.Lex("", src, false)); err != nil {
+				t.Errorf("unexpected error for %q: %s", src, err)
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		name, input string
+		strict      bool
+	}{
+		{name: "a CoverInitializedName that is not assigned to", input: "{a = 1}"},
+		{name: "a nested CoverInitializedName that is not assigned to", input: "[{a = 1}]"},
+		{name: "a CoverInitializedName in the value assigned", input: "a = {b = 1}"},
+		{name: "a CoverInitializedName in parentheses", input: "({a = 1})"},
+		{name: "a CoverInitializedName with a compound assignment", input: "{a = 1} += b"},
+		{name: "an element after a rest element", input: "[...a, b] = c"},
+		{name: "a comma after a rest element", input: "[...a,] = b"},
+		{name: "a rest element with an initializer", input: "[...a = 1] = b"},
+		{name: "a method in a pattern", input: "{a() {}} = b"},
+		{name: "a literal in a pattern", input: "{a: 1} = b"},
+		{name: "assigning to a literal", input: "1 = a"},
+		{name: "assigning to a pattern in parentheses", input: "({a}) = b"},
+		{name: "a compound assignment to a pattern", input: "[a] += b"},
+		{name: "assigning to eval in strict mode code", input: "eval = a", strict: true},
+	} {
+		t.Run("should not allow "+tc.name, func(t *testing.T) {
+			if _, err := es6.ParseAssignmentExpressionNode(es6.Lex("", tc.input, tc.strict)); err == nil {
+				t.Errorf("expected an error for %q", tc.input)
+			}
+		})
+	}
+}
+
+// sexpr renders an expression with parentheses around each operator
+func sexpr(n es6.ASTNode) string {
+	switch n := n.(type) {
+	case es6.ExpressionNode:
+		var list []string
+		for _, expression := range n.Expressions {
+			list = append(list, sexpr(expression))
+		}
+		return strings.Join(list, ", ")
+	case es6.AssignmentExpressionNode:
+		if n.Operator == "" {
+			return sexpr(n.Child)
+		}
+		return "(" + sexpr(n.Child) + " " + n.Operator + " " + sexpr(n.Value) + ")"
+	case es6.PrimaryExpressionNode:
+		return sexpr(n.Child)
+	case es6.ParenthesizedExpressionNode:
+		return sexpr(n.ExpressionNode)
+	case es6.IdentifierReferenceNode:
+		return n.Name
+	case es6.LiteralNode:
+		return fmt.Sprint(n.Number)
+	case es6.BinaryExpressionNode:
+		return "(" + sexpr(n.Left) + " " + n.Operator + " " + sexpr(n.Right) + ")"
+	case es6.LogicalExpressionNode:
+		return "(" + sexpr(n.Left) + " " + n.Operator + " " + sexpr(n.Right) + ")"
+	case es6.UnaryExpressionNode:
+		return "(" + n.Operator + " " + sexpr(n.Operand) + ")"
+	case es6.UpdateExpressionNode:
+		if n.Prefix {
+			return "(" + n.Operator + " " + sexpr(n.Operand) + ")"
+		}
+		return "(" + sexpr(n.Operand) + " " + n.Operator + ")"
+	case es6.ConditionalExpressionNode:
+		return "(" + sexpr(n.Test) + " ? " + sexpr(n.Consequent) + " : " + sexpr(n.Alternate) + ")"
+	case es6.MemberExpressionNode:
+		if n.Property != nil {
+			return sexpr(n.Object) + "[" + sexpr(n.Property) + "]"
+		}
+		return sexpr(n.Object) + "." + n.Name
+	case es6.CallExpressionNode:
+		return sexpr(n.Callee) + sexpr(n.Arguments)
+	case es6.NewExpressionNode:
+		if n.Arguments == nil {
+			return "(new " + sexpr(n.Callee) + ")"
+		}
+		return "(new " + sexpr(n.Callee) + sexpr(*n.Arguments) + ")"
+	case es6.ArgumentsNode:
+		var list []string
+		for _, argument := range n.List {
+			list = append(list, sexpr(argument))
+		}
+		return "(" + strings.Join(list, ", ") + ")"
+	case es6.SpreadElementNode:
+		return "..." + sexpr(n.Expression)
+	case es6.TaggedTemplateNode:
+		return sexpr(n.Tag) + "`" + strings.Join(n.Template.Raw, "${}") + "`"
+	default:
+		return fmt.Sprintf("%T", n)
+	}
+}
+
+func TestParseExpressionNode_Precedence(t *testing.T) {
+	for _, tc := range []struct{ input, want string }{
+		{input: "a + b * c", want: "(a + (b * c))"},
+		{input: "a * b + c", want: "((a * b) + c)"},
+		{input: "a - b - c", want: "((a - b) - c)"},
+		{input: "a ** b ** c", want: "(a ** (b ** c))"},
+		{input: "(-a) ** b", want: "((- a) ** b)"},
+		{input: "(a + b) * c", want: "((a + b) * c)"},
+		{
+			input: "a || b && c | d ^ e & f == g < h << i + j * k",
+			want:  "(a || (b && (c | (d ^ (e & (f == (g < (h << (i + (j * k))))))))))",
+		},
+		{input: "a * b || c == d", want: "((a * b) || (c == d))"},
+		{input: "a instanceof b in c", want: "((a instanceof b) in c)"},
+		{input: "a ? b : c ? d : e", want: "(a ? b : (c ? d : e))"},
+		{input: "a || b ? c = 1 : d", want: "((a || b) ? (c = 1) : d)"},
+		{input: "-a * !b", want: "((- a) * (! b))"},
+		{input: "typeof a.b", want: "(typeof a.b)"},
+		{input: "void delete a[0]", want: "(void (delete a[0]))"},
+		{input: "++a + b--", want: "((++ a) + (b --))"},
+		{input: "- -a", want: "(- (- a))"},
+		{input: "a = b ? c : d", want: "(a = (b ? c : d))"},
+		{input: "a = b += c", want: "(a = (b += c))"},
+		{input: "a.b /= c", want: "(a.b /= c)"},
+		{input: "a += b, c", want: "(a += b), c"},
+		{input: "a ?? b ?? c", want: "((a ?? b) ?? c)"},
+		{input: "(a || b) ?? c | d", want: "((a || b) ?? (c | d))"},
+		{input: "a ?? (b && c)", want: "(a ?? (b && c))"},
+		{input: "a ||= b &&= c ??= d", want: "(a ||= (b &&= (c ??= d)))"},
+		{input: "a / b / c", want: "((a / b) / c)"},
+		{input: "a.if.b[c](d)(e).f", want: "a.if.b[c](d)(e).f"},
+		{input: "new a.b(c).d(...e)", want: "(new a.b(c)).d(...e)"},
+		{input: "new new a()()", want: "(new (new a())())"},
+		{input: "new a", want: "(new a)"},
+		{input: "new a.b", want: "(new a.b)"},
+		{input: "a.b`c${d}e`", want: "a.b`c${}e`"},
+		{input: "a`\\unicode`", want: "a`\\unicode`"},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			node, err := es6.ParseExpressionNode(es6.Lex("", tc.input, false))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sexpr(node); got != tc.want {
+				t.Errorf("expected %s but got %s", tc.want, got)
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		name, input string
+		strict      bool
+	}{
+		{name: "a missing operand", input: "a +"},
+		{name: "a missing alternate", input: "a ? b"},
+		{name: "a unary operand of **", input: "-a ** b"},
+		{name: "assigning to a binary expression", input: "a + b = c"},
+		{name: "assigning to a call", input: "f() = a"},
+		{name: "incrementing an update expression", input: "++a++"},
+		{name: "incrementing a literal", input: "1++"},
+		{name: "a missing property name", input: "a."},
+		{name: "an unclosed argument list", input: "f(a"},
+		{name: "deleting an identifier in strict mode code", input: "delete (a)", strict: true},
+		{name: "?? after || without parentheses", input: "a || b ?? c"},
+		{name: "?? before && without parentheses", input: "a ?? b && c"},
+		{name: "&& before ?? without parentheses", input: "a && b ?? c"},
+		{name: "?? before || without parentheses", input: "a ?? b || c"},
+		{name: "logical assignment to a call", input: "f() ??= a"},
+	} {
+		t.Run("should not allow "+tc.name, func(t *testing.T) {
+			if _, err := es6.ParseExpressionNode(es6.Lex("", tc.input, tc.strict)); err == nil {
+				t.Errorf("expected an error for %q", tc.input)
+			}
+		})
+	}
+}
+
+func TestParseScriptNode_Edition(t *testing.T) {
+	for _, tc := range []struct {
+		input   string
+		edition es6.Edition
+	}{
+		{input: "a ?? b", edition: es6.ES2020},
+		{input: "a ??= b", edition: es6.ES2021},
+		{input: "a ||= b", edition: es6.ES2021},
+		{input: "a &&= b", edition: es6.ES2021},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			l := es6.Lex("", tc.input, false)
+			l.Edition = tc.edition - 1
+			if _, err := es6.ParseScriptNode(l); err == nil {
+				t.Errorf("expected an error for %q in %s", tc.input, l.Edition)
+			}
+			l = es6.Lex("", tc.input, false)
+			l.Edition = tc.edition
+			if _, err := es6.ParseScriptNode(l); err != nil {
+				t.Errorf("unexpected error for %q in %s: %s", tc.input, l.Edition, err)
+			}
+		})
+	}
+}
+
+func TestParseArrowFunctionNode(t *testing.T) {
+	for _, tc := range []struct {
+		input      string
+		parameters int
+		rest       bool
+		concise    bool
+	}{
+		{input: "a => a", parameters: 1, concise: true},
+		{input: "() => {}"},
+		{input: "(a, b = 1, ...c) => a", parameters: 2, rest: true, concise: true},
+		{input: "({a, b: [c]}, [d = 1]) => d", parameters: 2, concise: true},
+		{input: "([a, , ...b] = c) => { a }", parameters: 1},
+		{input: "(...a) => a", rest: true, concise: true},
+		{input: "a => b => a + b", parameters: 1, concise: true},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			node, err := es6.ParseAssignmentExpressionNode(es6.Lex("", tc.input, false))
+			if err != nil {
+				t.Fatal(err)
+			}
+			arrow, ok := node.Child.(es6.ArrowFunctionNode)
+			if !ok {
+				t.Fatalf("expected an ArrowFunctionNode but got %T", node.Child)
+			}
+			if got := len(arrow.Parameters.Formals); got != tc.parameters {
+				t.Errorf("expected %d parameters but got %d", tc.parameters, got)
+			}
+			if got := arrow.Parameters.Rest != nil; got != tc.rest {
+				t.Errorf("expected rest parameter to be %t", tc.rest)
+			}
+			if _, got := arrow.Body.Child.(es6.AssignmentExpressionNode); got != tc.concise {
+				t.Errorf("expected concise body to be %t but got %T", tc.concise, arrow.Body.Child)
+			}
+		})
+	}
+
+	t.Run("parentheses without an arrow", func(t *testing.T) {
+		node, err := es6.ParseAssignmentExpressionNode(es6.Lex("", "(a, b) + c", false))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := sexpr(node); got != "(a, b + c)" {
+			t.Errorf("expected (a, b + c) but got %s", got)
+		}
+	})
+
+	for _, tc := range []struct {
+		name, input string
+		strict      bool
+	}{
+		{name: "empty parentheses without an arrow", input: "()"},
+		{name: "a rest parameter without an arrow", input: "(a, ...b)"},
+		{name: "a line terminator before the arrow", input: "(a, b)\n=> a"},
+		{name: "a line terminator before the arrow of a single parameter", input: "a\n=> a"},
+		{name: "a literal parameter", input: "(1) => a"},
+		{name: "a member expression parameter", input: "(a.b) => a"},
+		{name: "a parenthesized parameter", input: "((a)) => a"},
+		{name: "duplicate parameters", input: "(a, [a]) => a"},
+		{name: "a method in a parameter pattern", input: "({a() {}}) => a"},
+		{name: "a rest element that is not last", input: "([...a, b]) => a"},
+		{name: "a CoverInitializedName without an arrow", input: "({a = 1})"},
+		{name: "eval as a parameter in strict mode code", input: "eval => a", strict: true},
+		{name: "arguments in a parameter pattern in strict mode code", input: "({arguments}) => a", strict: true},
+		{name: "an unclosed body", input: "a => { a"},
+	} {
+		t.Run("should not allow "+tc.name, func(t *testing.T) {
+			if _, err := es6.ParseAssignmentExpressionNode(es6.Lex("", tc.input, tc.strict)); err == nil {
+				t.Errorf("expected an error for %q", tc.input)
+			}
+		})
+	}
+}
+
+func TestParseFunctionDeclarationNode(t *testing.T) {
+	for _, tc := range []struct {
+		input  string
+		names  int
+		simple bool
+		strict bool
+	}{
+		{input: "function f() {}", simple: true},
+		{input: "function f(a, b) { return a }", names: 2, simple: true},
+		{input: "function f(a, a) {}", names: 2, simple: true},
+		{input: "function f(a = 1) {}", names: 1},
+		{input: "function f(...a) {}"},
+		{input: "function f({a, b: c}, [d, , e]) {}", names: 2},
+		{input: "function f(a) { 'use strict'; }", names: 1, simple: true, strict: true},
+		{input: "function f() { 'a'\n'use strict' }", simple: true, strict: true},
+		{input: "function f() { 'use strict' + a }", simple: true},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			node, err := es6.ParseFunctionDeclarationNode(es6.Lex("", tc.input, false))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if node.BindingIdentifier == nil || node.BindingIdentifier.Name != "f" {
+				t.Errorf("expected the function to be named f")
+			}
+			if got := len(node.FormalParameters.Formals); got != tc.names {
+				t.Errorf("expected %d parameters but got %d", tc.names, got)
+			}
+			if got := node.FormalParameters.IsSimpleParameterList(); got != tc.simple {
+				t.Errorf("expected IsSimpleParameterList to be %t", tc.simple)
+			}
+			if got := node.FunctionBody.UseStrict; got != tc.strict {
+				t.Errorf("expected UseStrict to be %t", tc.strict)
+			}
+		})
+	}
+
+	for _, tc := range []struct {
+		name, input string
+		strict      bool
+	}{
+		{name: "a missing name", input: "function () {}"},
+		{name: "duplicate parameters in strict mode code", input: "function f(a, a) {}", strict: true},
+		{name: "duplicate parameters with a rest parameter", input: "function f(a, ...a) {}"},
+		{name: "duplicate parameters in a pattern", input: "function f({a}, a) {}"},
+		{name: "duplicate parameters before a use strict directive", input: "function f(a, a) { 'use strict' }"},
+		{name: "a use strict directive with an initializer", input: "function f(a = 1) { 'use strict' }"},
+		{name: "a parameter named eval before a use strict directive", input: "function f(eval) { 'use strict' }"},
+		{name: "a function named arguments before a use strict directive", input: "function arguments() { 'use strict' }"},
+		{name: "a reserved word in the body after a use strict directive", input: "function f() { 'use strict'; let = 1 }"},
+		{name: "a legacy octal literal after a use strict directive", input: "function f() { \"use strict\"\n 010 }"},
+		{name: "an octal escape sequence after a use strict directive", input: "function f() { \"use strict\"\n '\\07' }"},
+		{name: "a missing parameter", input: "function f(a,) {}"},
+		{name: "an unclosed body", input: "function f() {"},
+	} {
+		t.Run("should not allow "+tc.name, func(t *testing.T) {
+			if _, err := es6.ParseFunctionDeclarationNode(es6.Lex("", tc.input, tc.strict)); err == nil {
+				t.Errorf("expected an error for %q", tc.input)
+			}
+		})
+	}
+}
+
+func TestParseFunctionExpressionNode(t *testing.T) {
+	for _, tc := range []struct {
+		input, name string
+	}{
+		{input: "function () {}"},
+		{input: "function f(a) { return function () { return a } }", name: "f"},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			node, err := es6.ParseFunctionExpressionNode(es6.Lex("", tc.input, false))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.name == "" && node.BindingIdentifier != nil {
+				t.Errorf("expected an anonymous function but got %s", node.BindingIdentifier.Name)
+			}
+			if tc.name != "" && (node.BindingIdentifier == nil || node.BindingIdentifier.Name != tc.name) {
+				t.Errorf("expected the function to be named %s", tc.name)
+			}
+		})
+	}
+}
+
+func TestParseBindingPatternNode(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  []string
+	}{
+		{input: "{}"},
+		{input: "[]"},
+		{input: "{a, b = 1, c: d, 'e': [f], [g]: {h},}", want: []string{"a", "b", "d", "f", "h"}},
+		{input: "[a, , b = 1, [c], ...d]", want: []string{"a", "b", "c", "d"}},
+		{input: "[, , ...a]", want: []string{"a"}},
+		{input: "[a, ,]", want: []string{"a"}},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			node, err := es6.ParseBindingPatternNode(es6.Lex("", tc.input, false))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bindingNames(node); strings.Join(got, " ") != strings.Join(tc.want, " ") {
+				t.Errorf("expected the names %q but got %q", tc.want, got)
+			}
+		})
+	}
+
+	for _, input := range []string{
+		"{a: 1}",
+		"{a.b}",
+		"[a.b]",
+		"[...a, b]",
+		"[...a = 1]",
+		"{a",
+		"[a",
+	} {
+		t.Run("should not allow "+input, func(t *testing.T) {
+			if _, err := es6.ParseBindingPatternNode(es6.Lex("", input, false)); err == nil {
+				t.Errorf("expected an error for %q", input)
+			}
+		})
+	}
+}
+
+// bindingNames returns the names bound by a binding in order
+func bindingNames(n es6.ASTNode) []string {
+	var names []string
+	switch n := n.(type) {
+	case es6.BindingPatternNode:
+		return bindingNames(n.Child)
+	case es6.BindingElementNode:
+		return bindingNames(n.Child)
+	case es6.SingleNameBindingNode:
+		return []string{n.Name.Name}
+	case es6.ObjectBindingPatternNode:
+		for _, property := range n.Properties {
+			names = append(names, bindingNames(property.Element)...)
+		}
+	case es6.ArrayBindingPatternNode:
+		for _, element := range n.Elements {
+			names = append(names, bindingNames(element)...)
+		}
+		if n.Rest != nil {
+			names = append(names, n.Rest.Name.Name)
+		}
+	}
+	return names
+}
+
+func TestParseExportDeclarationNode(t *testing.T) {
+	for _, input := range []string{
+		"export default function () {}",
+		"export default function f(a) { return a }",
+		"export default a => a;",
+	} {
+		t.Run(input, func(t *testing.T) {
+			l := es6.Lex("", input, true)
+			l.Module = true
+			node, err := es6.ParseExportDeclarationNode(l)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !node.Default {
+				t.Errorf("expected an export default")
+			}
+		})
+	}
+
+	for _, input := range []string{
+		"export * from 'a';",
+		"export { a };",
+		"export var a;",
+		"export function f() {}",
+	} {
+		t.Run("should not allow "+input, func(t *testing.T) {
+			l := es6.Lex("", input, true)
+			l.Module = true
+			if _, err := es6.ParseExportDeclarationNode(l); err == nil {
+				t.Errorf("expected an error for %q", input)
+			}
+		})
+	}
+}
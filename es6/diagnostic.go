@@ -0,0 +1,81 @@
+package es6
+
+import "fmt"
+
+// Diagnostic is an error found while lexing, it is reported at the position
+// where the problem was found
+type Diagnostic struct {
+	FilePosition
+	Message string
+	Code    DiagnosticCode
+}
+
+func (d Diagnostic) Error() string {
+	return fmt.Sprintf("%s %s", d.Message, d.FilePosition)
+}
+
+// DiagnosticCode identifies the kind of a Diagnostic
+type DiagnosticCode int
+
+const (
+	// UnexpectedCharacter is reported for a character that can not start
+	// any input element of the current goal
+	UnexpectedCharacter DiagnosticCode = iota
+	// UnterminatedComment is reported for a MultiLineComment without "*/"
+	UnterminatedComment
+	// UnterminatedStringLiteral is reported for a StringLiteral that reaches
+	// the end of input before its closing quote
+	UnterminatedStringLiteral
+	// UnterminatedTemplateLiteral is reported for a template that reaches the
+	// end of input before its closing backtick or a substitution
+	UnterminatedTemplateLiteral
+	// UnterminatedRegularExpression is reported for a
+	// RegularExpressionLiteral that reaches a LineTerminator or the end of
+	// input before its closing slash
+	UnterminatedRegularExpression
+	// InvalidRegularExpressionFlag is reported for an unknown or duplicated
+	// RegularExpressionFlag
+	InvalidRegularExpressionFlag
+	// InvalidEscapeSequence is reported for a malformed escape sequence or
+	// one that is not allowed where it appears
+	InvalidEscapeSequence
+	// InvalidNumericLiteral is reported for a malformed NumericLiteral
+	InvalidNumericLiteral
+	// LegacyNumericLiteral is reported for a legacy octal or leading zero
+	// decimal literal in strict mode code
+	LegacyNumericLiteral
+	// ReadError is reported when the underlying reader fails
+	ReadError
+	// UnsupportedSyntax is reported for syntax that was added after the
+	// Edition being lexed
+	UnsupportedSyntax
+)
+
+func (code DiagnosticCode) String() string {
+	switch code {
+	case UnexpectedCharacter:
+		return "UnexpectedCharacter"
+	case UnterminatedComment:
+		return "UnterminatedComment"
+	case UnterminatedStringLiteral:
+		return "UnterminatedStringLiteral"
+	case UnterminatedTemplateLiteral:
+		return "UnterminatedTemplateLiteral"
+	case UnterminatedRegularExpression:
+		return "UnterminatedRegularExpression"
+	case InvalidRegularExpressionFlag:
+		return "InvalidRegularExpressionFlag"
+	case InvalidEscapeSequence:
+		return "InvalidEscapeSequence"
+	case InvalidNumericLiteral:
+		return "InvalidNumericLiteral"
+	case LegacyNumericLiteral:
+		return "LegacyNumericLiteral"
+	case ReadError:
+		return "ReadError"
+	case UnsupportedSyntax:
+		return "UnsupportedSyntax"
+	default:
+		return "UnknownDiagnostic"
+	}
+}
@@ -0,0 +1,278 @@
+// Package highlight renders JavaScript source with syntax highlighting as
+// HTML or as text colored with ANSI escape sequences. The source is split
+// into tokens by es6.Tokenize so input that does not lex is still rendered,
+// the text that was skipped at an error, or the token that an error was
+// reported for, is highlighted as Error and the tokens after it are
+// highlighted as usual.
+package highlight
+
+import (
+	"html"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/crhntr/gobel/es6"
+)
+
+// Class is how a token is highlighted
+type Class int
+
+const (
+	// Plain is whitespace and line terminators, they are not highlighted
+	Plain Class = iota
+	Comment
+	Keyword
+	// Literal is null, true and false
+	Literal
+	Identifier
+	Number
+	String
+	Regexp
+	Template
+	Punctuator
+	// Error is the input that was skipped because it could not be lexed,
+	// or a token that was lexed with a diagnostic
+	Error
+)
+
+// String returns the name of the class, it is the CSS class of its tokens
+func (c Class) String() string {
+	switch c {
+	case Plain:
+		return "plain"
+	case Comment:
+		return "comment"
+	case Keyword:
+		return "keyword"
+	case Literal:
+		return "literal"
+	case Identifier:
+		return "identifier"
+	case Number:
+		return "number"
+	case String:
+		return "string"
+	case Regexp:
+		return "regexp"
+	case Template:
+		return "template"
+	case Punctuator:
+		return "punctuator"
+	case Error:
+		return "error"
+	default:
+		return "class" + strconv.Itoa(int(c))
+	}
+}
+
+// ClassOf returns the Class of tok on its own. A contextual keyword such as
+// let or of is an Identifier, it is only rendered as a Keyword when the
+// token after it shows that it is one
+func ClassOf(tok es6.Token) Class {
+	switch tok.Type {
+	case es6.MultiLineCommentToken, es6.SingleLineCommentToken, es6.HashbangCommentToken:
+		return Comment
+	case es6.ReservedWordToken:
+		switch tok.Value {
+		case "null", "true", "false":
+			return Literal
+		}
+		return Keyword
+	case es6.IdentifierNameToken, es6.PrivateNameToken:
+		return Identifier
+	case es6.NumericLiteralToken:
+		return Number
+	case es6.StringLiteralToken:
+		return String
+	case es6.RegExToken:
+		return Regexp
+	case es6.NoSubstitutionTemplateToken, es6.TemplateHeadToken, es6.TemplateMiddleToken, es6.TemplateTailToken:
+		return Template
+	case es6.PunctuatorToken, es6.DivPunctuatorToken, es6.RightBracePunctuatorToken:
+		return Punctuator
+	case es6.ErrorToken:
+		return Error
+	default:
+		return Plain
+	}
+}
+
+// DefaultColors are the SGR parameters used by ANSI when a Renderer has no
+// Colors
+var DefaultColors = map[Class]string{
+	Comment:  "90",
+	Keyword:  "35",
+	Literal:  "36",
+	Number:   "36",
+	String:   "32",
+	Regexp:   "31",
+	Template: "32",
+	Error:    "4;31",
+}
+
+// Renderer renders highlighted source, the zero value is ready to use
+type Renderer struct {
+	// AnchorPrefix is put before the line number to make the id of the
+	// anchor at the start of each line of HTML, it is "L" when empty
+	AnchorPrefix string
+	// Colors are the SGR parameters used for each Class by ANSI, a Class
+	// without one is not colored. DefaultColors is used when it is nil
+	Colors map[Class]string
+}
+
+// HTML renders src with the zero Renderer
+func HTML(w io.Writer, src string) error {
+	return Renderer{}.HTML(w, src)
+}
+
+// ANSI renders src with the zero Renderer
+func ANSI(w io.Writer, src string) error {
+	return Renderer{}.ANSI(w, src)
+}
+
+// HTML writes src to w as a pre element with the class js. Each token that
+// is not Plain is a span with its Class as its CSS class and each line
+// starts with an anchor to itself, with the class line-number, so that
+// lines can be linked to.
+func (r Renderer) HTML(w io.Writer, src string) error {
+	prefix := r.AnchorPrefix
+	if prefix == "" {
+		prefix = "L"
+	}
+	ew := &errWriter{w: w}
+	ew.WriteString(`<pre class="js">`)
+	render(src, func(line int) {
+		id := html.EscapeString(prefix + strconv.Itoa(line))
+		ew.WriteString(`<a id="` + id + `" href="#` + id + `" class="line-number">` + strconv.Itoa(line) + `</a>`)
+	}, func(c Class, text string) {
+		if c == Plain {
+			ew.WriteString(html.EscapeString(text))
+			return
+		}
+		ew.WriteString(`<span class="` + c.String() + `">` + html.EscapeString(text) + `</span>`)
+	})
+	ew.WriteString("</pre>\n")
+	return ew.err
+}
+
+// ANSI writes src to w with each token colored by an ANSI escape sequence.
+// A token over several lines is colored on each line so that every line
+// can be printed on its own.
+func (r Renderer) ANSI(w io.Writer, src string) error {
+	colors := r.Colors
+	if colors == nil {
+		colors = DefaultColors
+	}
+	ew := &errWriter{w: w}
+	render(src, func(int) {}, func(c Class, text string) {
+		if color, ok := colors[c]; ok {
+			ew.WriteString("\x1b[" + color + "m" + text + "\x1b[0m")
+			return
+		}
+		ew.WriteString(text)
+	})
+	return ew.err
+}
+
+// render calls line at the start of each line of src and text for each part
+// of a token on a line. Each LineTerminator is passed to text as a Plain
+// "\n", a line is only started when something follows the LineTerminator
+// before it.
+func render(src string, line func(n int), text func(c Class, s string)) {
+	n := 0
+	started := false
+	tokens := es6.Tokenize("", src)
+	for i, tok := range tokens {
+		c := classAt(tokens, i)
+		for s := tok.Source; s != ""; {
+			if !started {
+				n++
+				line(n)
+				started = true
+			}
+			i := strings.IndexAny(s, lineTerminators)
+			if i < 0 {
+				text(c, s)
+				break
+			}
+			if i > 0 {
+				text(c, s[:i])
+			}
+			text(Plain, "\n")
+			started = false
+			s = s[i+terminatorLength(s[i:]):]
+		}
+	}
+}
+
+// classAt returns the Class of tokens[i] using the tokens around it. A
+// contextual keyword is a Keyword when it is followed by a name or the
+// start of a pattern, as in let a, of [b] or get c. A token that an error
+// was reported for, which follows an ErrorToken that skipped no input, is
+// an Error
+func classAt(tokens []es6.Token, i int) Class {
+	tok := tokens[i]
+	if i > 0 && tok.Type != es6.ErrorToken && tokens[i-1].Type == es6.ErrorToken && tokens[i-1].Source == "" {
+		return Error
+	}
+	if !tok.Contextual {
+		return ClassOf(tok)
+	}
+	next := es6.Token{Type: es6.EOFToken}
+	for _, t := range tokens[i+1:] {
+		if !isTrivia(t) {
+			next = t
+			break
+		}
+	}
+	switch next.Type {
+	case es6.IdentifierNameToken, es6.PrivateNameToken, es6.StringLiteralToken, es6.NumericLiteralToken:
+		return Keyword
+	case es6.ReservedWordToken:
+		if next.Value != "in" && next.Value != "instanceof" {
+			return Keyword
+		}
+	case es6.PunctuatorToken:
+		if next.Value == "[" || next.Value == "{" {
+			return Keyword
+		}
+	}
+	return Identifier
+}
+
+// isTrivia reports whether tok is whitespace, a line terminator or a comment
+func isTrivia(tok es6.Token) bool {
+	switch tok.Type {
+	case es6.WhiteSpaceToken, es6.LineTerminatorToken, es6.MultiLineCommentToken, es6.SingleLineCommentToken, es6.HashbangCommentToken:
+		return true
+	default:
+		return false
+	}
+}
+
+const lineTerminators = "\r\n\u2028\u2029"
+
+// terminatorLength returns the length of the LineTerminatorSequence at the
+// start of s
+func terminatorLength(s string) int {
+	if strings.HasPrefix(s, "\r\n") {
+		return len("\r\n")
+	}
+	if s[0] == '\r' || s[0] == '\n' {
+		return 1
+	}
+	return len("\u2028") // or \u2029
+}
+
+// errWriter keeps the first error returned by w and skips writes after it
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) WriteString(s string) {
+	if ew.err == nil {
+		_, ew.err = io.WriteString(ew.w, s)
+	}
+}
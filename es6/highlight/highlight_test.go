@@ -0,0 +1,126 @@
+package highlight_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crhntr/gobel/es6"
+	"github.com/crhntr/gobel/es6/highlight"
+)
+
+func TestClassOf(t *testing.T) {
+	for _, row := range []struct {
+		js       string
+		expected []highlight.Class
+	}{
+		{"if (null) x = 1", []highlight.Class{highlight.Keyword, highlight.Punctuator, highlight.Literal, highlight.Punctuator, highlight.Identifier, highlight.Punctuator, highlight.Number}},
+		{"'a' + /b/g", []highlight.Class{highlight.String, highlight.Punctuator, highlight.Regexp}},
+		{"`a${b}c` // d", []highlight.Class{highlight.Template, highlight.Identifier, highlight.Template, highlight.Comment}},
+	} {
+		var classes []highlight.Class
+		for _, tok := range es6.Tokenize("", row.js) {
+			if c := highlight.ClassOf(tok); c != highlight.Plain {
+				classes = append(classes, c)
+			}
+		}
+		if len(classes) != len(row.expected) {
+			t.Errorf("%s: expected %v but got %v", row.js, row.expected, classes)
+			continue
+		}
+		for i := range classes {
+			if classes[i] != row.expected[i] {
+				t.Errorf("%s: expected %v but got %v", row.js, row.expected, classes)
+				break
+			}
+		}
+	}
+}
+
+func TestHTML(t *testing.T) {
+	js := "a < b /* c\n d */\n\n`e`"
+	expected := `<pre class="js">` +
+		`<a id="L1" href="#L1" class="line-number">1</a><span class="identifier">a</span> <span class="punctuator">&lt;</span> <span class="identifier">b</span> <span class="comment">/* c</span>` + "\n" +
+		`<a id="L2" href="#L2" class="line-number">2</a><span class="comment"> d */</span>` + "\n" +
+		`<a id="L3" href="#L3" class="line-number">3</a>` + "\n" +
+		`<a id="L4" href="#L4" class="line-number">4</a><span class="template">` + "`e`" + `</span>` +
+		"</pre>\n"
+	var b strings.Builder
+	if err := highlight.HTML(&b, js); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != expected {
+		t.Errorf("expected\n%s\nbut got\n%s", expected, b.String())
+	}
+}
+
+func TestHTML_AnchorPrefix(t *testing.T) {
+	var b strings.Builder
+	if err := (highlight.Renderer{AnchorPrefix: "snippet-1-"}).HTML(&b, "x"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), `<a id="snippet-1-1" href="#snippet-1-1"`) {
+		t.Errorf("expected the anchor to use the prefix but got %s", b.String())
+	}
+}
+
+func TestHTML_Error(t *testing.T) {
+	var b strings.Builder
+	if err := highlight.HTML(&b, "a @ b"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), `<span class="error">@</span> <span class="identifier">b</span>`) {
+		t.Errorf("expected the skipped input to be an error and the rest to be highlighted but got %s", b.String())
+	}
+}
+
+func TestHTML_ContextualKeyword(t *testing.T) {
+	for _, tc := range []struct{ js, expected string }{
+		{js: "let a", expected: `<span class="keyword">let</span> <span class="identifier">a</span>`},
+		{js: "let [a] = b", expected: `<span class="keyword">let</span> <span class="punctuator">[</span>`},
+		{js: "for (a of b)", expected: `<span class="keyword">of</span> <span class="identifier">b</span>`},
+		{js: "let = of", expected: `<span class="identifier">let</span> <span class="punctuator">=</span> <span class="identifier">of</span>`},
+		{js: "a.let", expected: `<span class="punctuator">.</span><span class="identifier">let</span>`},
+	} {
+		var b strings.Builder
+		if err := highlight.HTML(&b, tc.js); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(b.String(), tc.expected) {
+			t.Errorf("%s: expected %s in %s", tc.js, tc.expected, b.String())
+		}
+	}
+}
+
+func TestHTML_Diagnostic(t *testing.T) {
+	var b strings.Builder
+	if err := highlight.HTML(&b, "a = 'b\nc"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), `<span class="error">&#39;b</span>`+"\n") {
+		t.Errorf("expected the unterminated string to be an error but got %s", b.String())
+	}
+	if !strings.Contains(b.String(), `<span class="identifier">c</span>`) {
+		t.Errorf("expected the tokens after the error to be highlighted but got %s", b.String())
+	}
+}
+
+func TestANSI(t *testing.T) {
+	js := "if /* a\r\nb */ 'c'\n"
+	expected := "\x1b[35mif\x1b[0m \x1b[90m/* a\x1b[0m\n\x1b[90mb */\x1b[0m \x1b[32m'c'\x1b[0m\n"
+	var b strings.Builder
+	if err := highlight.ANSI(&b, js); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != expected {
+		t.Errorf("expected %q but got %q", expected, b.String())
+	}
+
+	b.Reset()
+	colors := map[highlight.Class]string{highlight.String: "1"}
+	if err := (highlight.Renderer{Colors: colors}).ANSI(&b, js); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "if /* a\nb */ \x1b[1m'c'\x1b[0m\n"; b.String() != expected {
+		t.Errorf("expected %q but got %q", expected, b.String())
+	}
+}
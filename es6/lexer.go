@@ -2,7 +2,7 @@ package es6
 
 import (
 	"fmt"
-	"sort"
+	"io"
 	"strings"
 	"unicode/utf16"
 	"unicode/utf8"
@@ -11,14 +11,15 @@ import (
 // Lex lexes a string into tokens
 func Lex(name, input string, safe bool) *Lexer {
 	l := &Lexer{
-		name:   name,
-		input:  input,
-		state:  lexInputElement,
-		tokens: []Token{},
-		strict: true,
-		goal:   InputElementDiv,
-		line:   1,
-		column: 0,
+		consumed: &tokenLog{},
+		name:     name,
+		input:    []byte(input),
+		state:    lexInputElement,
+		tokens:   []Token{},
+		strict:   true,
+		goal:     InputElementDiv,
+		line:     1,
+		column:   0,
 	}
 	if safe {
 		l.setStrict()
@@ -28,23 +29,70 @@ func Lex(name, input string, safe bool) *Lexer {
 	return l
 }
 
+// NewLexer returns a Lexer that reads its input from r as it is needed
+// instead of requiring the whole source to be held in memory. Any error
+// returned by r, other than io.EOF, is reported as an ErrorToken and is
+// available from Err
+func NewLexer(name string, r io.Reader, safe bool) *Lexer {
+	l := Lex(name, "", safe)
+	l.reader = r
+	return l
+}
+
 // A Lexer represents the state of the lexing algorithm use func Lex to return
 // an initalized Lexer
 type Lexer struct {
 	name                    string // used for error reports
 	state                   stateFunc
-	input                   string  // the string being scanned
-	start                   int     // start position of this item
-	pos                     int     // current position of this input
-	width                   int     // width of last rune read
-	tokens                  []Token // chan Token // channel if scanned tokens
-	reservedWords           []string
+	input                   []byte    // the window of input being scanned, token values are copied out of it
+	offset                  int       // offset of the window in the source
+	reader                  io.Reader // source of input not yet in the window
+	readErr                 error     // first error returned by reader
+	readErrReported         bool      // readErr has been reported as a Diagnostic
+	chunk                   []byte    // buffer reused for reads
+	start                   int       // start position of this item
+	pos                     int       // current position of this input
+	width                   int       // width of last rune read
+	tokens                  []Token   // chan Token // channel if scanned tokens
+	newlineBefore           bool      // a LineTerminator was lexed since the last significant token
+	comments                []Token   // comments lexed since the last significant token
+	afterToken              bool      // a significant token has been lexed
+	trailingTaken           bool      // the trailing comments of the last token were attached to a node
+	diagnostics             []Diagnostic
+	marks                   []int      // offsets of the checkpoints that pin the window
+	runs                    []lexState // state before each run of lex that produced buffered tokens
+	seq                     int        // number of tokens removed from the front of tokens
 	strict                  bool
 	goal                    LexerGoal
+	coverInitializedName    *FilePosition // the first CoverInitializedName the parser has not found assigned to
+	inFunctionBody          bool          // the parser is in the body of a function, where a ReturnStatement is allowed
 	CaptureWhitespaceTokens bool
+	// UTF16Columns makes columns count UTF-16 code units, as browsers and
+	// source maps do, instead of code points
+	UTF16Columns bool
+	// Edition is the ECMAScript edition the input is lexed as, syntax that
+	// was added in a later edition is reported as UnsupportedSyntax. The
+	// zero value is LatestEdition
+	Edition Edition
+	// Module makes the input lexed as module code, which is always strict
+	// mode code and so does not allow HTML-like comments
+	Module bool
+	// ConcreteSyntax makes the parser attach every token it consumes to the
+	// tree, including the whitespace, line terminators and comments that
+	// Next skips, so that Print can write the source back unchanged
+	ConcreteSyntax bool
+
+	line     int       // line of start
+	column   int       // column of start
+	afterCR  bool      // the input before start ends with a carriage return
+	consumed *tokenLog // every token removed by Next when ConcreteSyntax is set
+}
 
-	line   int
-	column int
+// tokenLog holds the tokens consumed in ConcreteSyntax mode. It is shared by
+// the Lexer and the nodes parsed from it, which keep the range of it they
+// span instead of a copy
+type tokenLog struct {
+	tokens []Token
 }
 
 // LexerGoal represents a lexing goal
@@ -71,6 +119,41 @@ const (
 	InputElementTemplateTail
 )
 
+// Edition is a yearly edition of ECMAScript
+type Edition int
+
+// Editions that added syntax to the lexical grammar
+const (
+	ES2015 Edition = 2015 + iota
+	ES2016
+	ES2017
+	ES2018
+	ES2019
+	ES2020
+	ES2021
+	ES2022
+
+	LatestEdition = ES2022
+)
+
+func (edition Edition) String() string {
+	return fmt.Sprintf("ES%d", int(edition))
+}
+
+// supports reports whether syntax added in edition is lexed without an
+// UnsupportedSyntax diagnostic
+func (l *Lexer) supports(edition Edition) bool {
+	return l.Edition == 0 || l.Edition >= edition
+}
+
+// requires reports the syntax described by what when it was added after the
+// edition being lexed
+func (l *Lexer) requires(edition Edition, what string) {
+	if !l.supports(edition) {
+		l.errorf(UnsupportedSyntax, "%s requires %s", what, edition)
+	}
+}
+
 func (goal LexerGoal) String() string {
 	switch goal {
 	case InputElementDiv:
@@ -86,46 +169,410 @@ func (goal LexerGoal) String() string {
 	}
 }
 
-// Next returns the next token
+// Next returns the next token. Unless CaptureWhitespaceTokens is set
+// whitespace, line terminators and comments are skipped, the comments are
+// available from the LeadingComments of the token that follows them or
+// from TrailingComments.
 func (l *Lexer) Next(goal LexerGoal) Token {
+	l.goal = goal
 	for {
-		l.goal = goal
-		l.state = lexInputElement
-		for len(l.tokens) < 1 {
-			l.state = l.state(l)
+		if len(l.tokens) == 0 {
+			l.lex()
 		}
 		tok := l.tokens[0]
-		l.tokens = l.tokens[1:]
+		if !l.skipped(tok) && tok.Goal != goal && tok.Type.isGoalSensitive() && l.relex(0) {
+			continue
+		}
+		l.pop()
+		if l.ConcreteSyntax {
+			l.consumed.tokens = append(l.consumed.tokens, tok)
+		}
 
-		if tok.Type != WhiteSpaceToken || l.CaptureWhitespaceTokens {
+		if !l.skipped(tok) {
+			l.trailingTaken = false
 			return tok
 		}
 	}
 }
 
-// Peek returns the next token
+// Peek returns the next token without consuming it
 func (l *Lexer) Peek(goal LexerGoal) Token {
-	for {
-		tok := l.Next(goal)
-		l.tokens = append(l.tokens, tok)
+	return l.PeekN(1, goal)
+}
 
-		if !l.CaptureWhitespaceTokens && tok.Type == WhiteSpaceToken {
+// PeekN returns the nth next token without consuming it, PeekN(1, goal) is
+// the same as Peek(goal). Tokens that have not been lexed yet are lexed with
+// goal. When the nth token was lexed with a different goal and it could be
+// lexed differently with goal it is lexed again, along with the tokens after
+// it.
+func (l *Lexer) PeekN(n int, goal LexerGoal) Token {
+	l.goal = goal
+	for i := 0; ; i++ {
+		if i == len(l.tokens) {
+			l.lex()
+		}
+		tok := l.tokens[i]
+		if l.skipped(tok) {
+			continue
+		}
+		if n--; n > 0 {
+			continue
+		}
+		if tok.Goal != goal && tok.Type.isGoalSensitive() && l.relex(i) {
+			i--
+			n++
 			continue
 		}
 		return tok
 	}
 }
 
+// TrailingComments returns the comments that follow the last token returned
+// by Next when they are on the same line as it and nothing else follows them
+// on that line. Comments followed by another token on the same line are
+// leading comments of that token instead.
+func (l *Lexer) TrailingComments() []Token {
+	var comments []Token
+	for i := 0; ; i++ {
+		for i == len(l.tokens) {
+			state := triviaState(l)
+			if state == nil {
+				if l.atEOF() {
+					return comments
+				}
+				return nil
+			}
+			state(l)
+		}
+		switch tok := l.tokens[i]; {
+		case tok.NewlineBefore, tok.Type == LineTerminatorToken, tok.Type == EOFToken:
+			return comments
+		case tok.Type == MultiLineCommentToken, tok.Type == SingleLineCommentToken, tok.Type == HashbangCommentToken:
+			comments = append(comments, tok)
+		case !tok.Type.isTrivia():
+			return nil
+		}
+	}
+}
+
+// takeLeadingComments returns the leading comments of the next token, each
+// comment is only returned once so that it is attached to a single node
+func (l *Lexer) takeLeadingComments() []Token {
+	l.Peek(l.goal)
+	for i, tok := range l.tokens {
+		if !l.skipped(tok) {
+			l.tokens[i].LeadingComments = nil
+			return tok.LeadingComments
+		}
+	}
+	return nil
+}
+
+// takeTrailingComments returns the trailing comments of the last token
+// returned by Next, they are only returned once per token
+func (l *Lexer) takeTrailingComments() []Token {
+	if l.trailingTaken {
+		return nil
+	}
+	l.trailingTaken = true
+	return l.TrailingComments()
+}
+
+// lex runs the state machine until at least one more token is buffered. A
+// state that stopped at an error leaves the input it consumed pending, that
+// input is skipped as the Source of the ErrorToken so lexing resumes at the
+// next token boundary
+func (l *Lexer) lex() {
+	if l.Module && !l.strict {
+		l.setStrict()
+	}
+	state := l.saveState()
+	n := len(l.tokens)
+	l.state = lexInputElement
+	for len(l.tokens) == n {
+		l.state = l.state(l)
+	}
+	if l.pos > l.start {
+		// the state stopped at an error, the input it consumed is skipped
+		l.tokens[len(l.tokens)-1].Source = string(l.input[l.start:l.pos])
+		l.ignore()
+	}
+	sensitive := false
+	for i := n; i < len(l.tokens); i++ {
+		l.tokens[i].Goal = l.goal
+		sensitive = sensitive || l.tokens[i].Type.isGoalSensitive()
+	}
+	// only a run with a token that could be lexed differently with another
+	// goal is ever lexed again, so the state before any other run is not kept
+	if sensitive {
+		l.runs = append(l.runs, state)
+	}
+}
+
+// lexState is the state of the Lexer at start
+type lexState struct {
+	seq             int // sequence number of the next token
+	offset          int // offset of start in the source
+	line, column    int
+	afterCR         bool
+	diagnostics     int
+	readErrReported bool
+	newlineBefore   bool
+	comments        []Token // shares the array of l.comments, appending to it copies it
+	afterToken      bool
+}
+
+func (l *Lexer) saveState() lexState {
+	return lexState{
+		seq:             l.seq + len(l.tokens),
+		offset:          l.offset + l.start,
+		line:            l.line,
+		column:          l.column,
+		afterCR:         l.afterCR,
+		diagnostics:     len(l.diagnostics),
+		readErrReported: l.readErrReported,
+		newlineBefore:   l.newlineBefore,
+		comments:        l.comments[:len(l.comments):len(l.comments)],
+		afterToken:      l.afterToken,
+	}
+}
+
+// restoreState returns the Lexer to state, the tokens lexed after it are
+// removed from the buffer
+func (l *Lexer) restoreState(state lexState) {
+	l.tokens = l.tokens[:state.seq-l.seq]
+	l.start = state.offset - l.offset
+	l.pos = l.start
+	l.width = 0
+	l.line, l.column, l.afterCR = state.line, state.column, state.afterCR
+	l.diagnostics = l.diagnostics[:state.diagnostics]
+	l.readErrReported = state.readErrReported
+	l.newlineBefore = state.newlineBefore
+	l.comments = state.comments
+	l.afterToken = state.afterToken
+}
+
+// relex removes the token at index i of the buffer, and the tokens after it,
+// so that they are lexed again. It returns false when that is not possible
+// because a token lexed along with it has already been consumed
+func (l *Lexer) relex(i int) bool {
+	seq := l.seq + i
+	k := len(l.runs) - 1
+	for k > 0 && l.runs[k].seq > seq {
+		k--
+	}
+	if k < 0 || l.runs[k].seq < l.seq || l.runs[k].seq > seq {
+		return false
+	}
+	l.restoreState(l.runs[k])
+	l.runs = l.runs[:k]
+	return true
+}
+
+// pop removes the first token from the buffer
+func (l *Lexer) pop() {
+	l.seq++
+	if len(l.tokens) == 1 {
+		// the buffer is empty so its array is reused for the next tokens
+		l.tokens = l.tokens[:0]
+		l.runs = l.runs[:0]
+		return
+	}
+	l.tokens = l.tokens[1:]
+	for len(l.runs) > 1 && l.runs[1].seq <= l.seq {
+		l.runs = l.runs[1:]
+	}
+}
+
+// skipped reports whether Next and Peek pass over tok
+func (l *Lexer) skipped(tok Token) bool {
+	return !l.CaptureWhitespaceTokens && tok.Type.isTrivia()
+}
+
 // CurrentPosition returns the Lexer's current position
 func (l *Lexer) CurrentPosition() FilePosition {
+	line, column, _ := l.positionAt(l.pos)
+	return FilePosition{
+		FileName: l.name,
+		Offset:   l.offset + l.pos,
+		Line:     line,
+		Column:   column,
+	}
+}
+
+// startPosition returns the position of start, where the token being lexed
+// begins
+func (l *Lexer) startPosition() FilePosition {
 	return FilePosition{
 		FileName: l.name,
-		Offset:   l.pos,
+		Offset:   l.offset + l.start,
 		Line:     l.line,
 		Column:   l.column,
 	}
 }
 
+// positionAt returns the line and column of end, which must not be before
+// start, by counting from the position of start. A carriage return followed
+// by a line feed is a single line terminator
+func (l *Lexer) positionAt(end int) (line, column int, afterCR bool) {
+	line, column, afterCR = l.line, l.column, l.afterCR
+	for _, r := range string(l.input[l.start:end]) {
+		switch {
+		case r == '\n' && afterCR:
+		case isLineTerminator(r):
+			line++
+			column = 0
+		case l.UTF16Columns && r >= 0x10000:
+			column += 2
+		default:
+			column++
+		}
+		afterCR = r == '\r'
+	}
+	return line, column, afterCR
+}
+
+// moveStart moves start forward to end keeping track of its position
+func (l *Lexer) moveStart(end int) {
+	l.line, l.column, l.afterCR = l.positionAt(end)
+	l.start = end
+}
+
+// Diagnostics returns the errors found in the input so far, in the order
+// they were found. Each one is also returned by Next as an ErrorToken
+func (l *Lexer) Diagnostics() []Diagnostic {
+	return l.diagnostics
+}
+
+// Err returns the first error, other than io.EOF, that was encountered
+// while reading the input
+func (l *Lexer) Err() error {
+	if l.readErr == io.EOF {
+		return nil
+	}
+	return l.readErr
+}
+
+// readChunkSize is the number of bytes requested from the reader each
+// time the window needs more input
+const readChunkSize = 4096
+
+// fill reads from the underlying reader until at least n bytes are
+// available after pos or the reader is exhausted. Input before start has
+// already been emitted so it is dropped as the window slides forward.
+func (l *Lexer) fill(n int) bool {
+	for len(l.input)-l.pos < n {
+		if l.reader == nil || l.readErr != nil {
+			return false
+		}
+		if l.chunk == nil {
+			l.chunk = make([]byte, readChunkSize)
+		}
+		m, err := l.reader.Read(l.chunk)
+		if err != nil {
+			l.readErr = err
+		}
+		l.slide()
+		l.input = append(l.input, l.chunk[:m]...)
+	}
+	return true
+}
+
+// slide discards the input before start that is not needed to reset to a
+// Checkpoint
+func (l *Lexer) slide() {
+	drop := l.start
+	for _, mark := range l.marks {
+		if mark-l.offset < drop {
+			drop = mark - l.offset
+		}
+	}
+	if len(l.runs) > 0 && l.runs[0].offset-l.offset < drop {
+		drop = l.runs[0].offset - l.offset
+	}
+	if drop <= 0 {
+		return
+	}
+	l.input = l.input[drop:]
+	l.offset += drop
+	l.pos -= drop
+	l.start -= drop
+}
+
+// Checkpoint is a state of the Lexer returned by Mark
+type Checkpoint struct {
+	lexState
+	goal          LexerGoal
+	tokens        []Token
+	runs          []lexState
+	seq           int
+	trailingTaken bool
+	consumed      int
+	// the parser state kept on the Lexer, which a parse attempt can change
+	strict               bool
+	coverInitializedName *FilePosition
+	inFunctionBody       bool
+}
+
+// Mark returns a Checkpoint that Reset can return the Lexer to, it is used
+// to backtrack when a parse attempt fails. The input after a Checkpoint is
+// kept in memory until the Checkpoint is released
+func (l *Lexer) Mark() Checkpoint {
+	cp := Checkpoint{
+		lexState:      l.saveState(),
+		goal:          l.goal,
+		tokens:        append([]Token(nil), l.tokens...),
+		runs:          append([]lexState(nil), l.runs...),
+		seq:           l.seq,
+		trailingTaken: l.trailingTaken,
+		consumed:      len(l.consumed.tokens),
+
+		strict:               l.strict,
+		coverInitializedName: l.coverInitializedName,
+		inFunctionBody:       l.inFunctionBody,
+	}
+	l.marks = append(l.marks, cp.offset)
+	return cp
+}
+
+// Reset returns the Lexer to the state it was in when cp was returned by
+// Mark, tokens returned by Next since then are returned again and
+// diagnostics found since then are forgotten. A Checkpoint can be reset to
+// any number of times until it is released
+func (l *Lexer) Reset(cp Checkpoint) {
+	l.seq = cp.seq
+	l.tokens = append([]Token(nil), cp.tokens...)
+	l.restoreState(cp.lexState)
+	l.goal = cp.goal
+	l.runs = append([]lexState(nil), cp.runs...)
+	l.trailingTaken = cp.trailingTaken
+	l.consumed.tokens = l.consumed.tokens[:cp.consumed]
+	l.strict = cp.strict
+	l.coverInitializedName = cp.coverInitializedName
+	l.inFunctionBody = cp.inFunctionBody
+}
+
+// Release unpins the input kept for cp, it must not be reset to afterwards
+func (l *Lexer) Release(cp Checkpoint) {
+	for i, mark := range l.marks {
+		if mark == cp.offset {
+			l.marks = append(l.marks[:i], l.marks[i+1:]...)
+			return
+		}
+	}
+}
+
+// hasPrefix reports whether the unread input begins with str
+func (l *Lexer) hasPrefix(str string) bool {
+	l.fill(len(str))
+	return len(l.input)-l.pos >= len(str) && string(l.input[l.pos:l.pos+len(str)]) == str
+}
+
+// atEOF reports whether all of the input has been read
+func (l *Lexer) atEOF() bool {
+	return !l.fill(1)
+}
+
 const eof rune = -1
 
 // func (l lexer) String() string {
@@ -135,49 +582,80 @@ const eof rune = -1
 
 func (l *Lexer) setStrict() {
 	l.strict = true
-	l.reservedWords = []string{}
-	l.reservedWords = append(currentReservedWords, futureReservedWords...)
-	l.reservedWords = append(currentReservedWords, literals...)
-	l.reservedWords = append(l.reservedWords, futureResdervedWordsStrict...)
-	sort.Sort(keywordSorter(l.reservedWords))
 }
 
 func (l *Lexer) unsetStrict() {
 	l.strict = false
-	l.reservedWords = []string{}
-	l.reservedWords = append(currentReservedWords, futureReservedWords...)
-	l.reservedWords = append(currentReservedWords, literals...)
-	sort.Sort(keywordSorter(l.reservedWords))
 }
 
 type stateFunc func(*Lexer) stateFunc
 
 // emit passes an item back to the client.
 func (l *Lexer) emit(typ TokenType) {
-	val := l.input[l.start:l.pos]
+	l.emitToken(Token{Type: typ})
+}
+
+// emitComment passes a comment back to the client, its value is its source
+// without the open and close delimiters
+func (l *Lexer) emitComment(typ TokenType, open, close string) {
+	source := string(l.input[l.start:l.pos])
+	l.emitToken(Token{Type: typ, Source: source, Value: source[len(open) : len(source)-len(close)]})
+}
+
+// emitToken passes an item back to the client after setting its source,
+// value and position, any other fields of tok are left as they are. The
+// source is only copied from the input when tok does not already have it,
+// and the value of a comment is set by emitComment
+func (l *Lexer) emitToken(tok Token) {
+	if tok.Source == "" {
+		tok.Source = string(l.input[l.start:l.pos])
+	}
+	if !tok.Type.isComment() {
+		tok.Value = tok.Source
+	}
+	tok.FilePosition = l.startPosition()
+	tok.NewlineBefore = l.newlineBefore
+	switch {
+	case tok.Type == LineTerminatorToken:
+		l.newlineBefore = true
+	case tok.Type == MultiLineCommentToken:
+		l.comments = append(l.comments, tok)
+		l.newlineBefore = l.newlineBefore || strings.ContainsAny(tok.Value, lineTerminators)
+	case tok.Type == SingleLineCommentToken, tok.Type == HashbangCommentToken:
+		l.comments = append(l.comments, tok)
+	case !tok.Type.isTrivia():
+		tok.LeadingComments = l.leadingComments(tok)
+		l.comments = nil
+		l.afterToken = true
+		l.newlineBefore = false
+	}
 	// l.tokens <- Token{typ, val}
-	l.tokens = append(
-		l.tokens,
-		Token{
-			Type:  typ,
-			Value: val,
-			FilePosition: FilePosition{
-				FileName: l.name,
-				Offset:   l.pos,
-				Line:     l.line,
-				Column:   l.column,
-			},
-		},
-	)
-	l.start = l.pos
+	l.tokens = append(l.tokens, tok)
+	l.moveStart(l.pos)
+}
+
+// leadingComments returns the comments lexed before tok that are not
+// trailing comments of the token before it
+func (l *Lexer) leadingComments(tok Token) []Token {
+	if !l.afterToken || !(tok.NewlineBefore || tok.Type == EOFToken) {
+		return l.comments
+	}
+	var comments []Token
+	for _, comment := range l.comments {
+		if comment.NewlineBefore {
+			comments = append(comments, comment)
+		}
+	}
+	return comments
 }
 
 func (l *Lexer) next() (r rune) {
+	l.fill(utf8.UTFMax)
 	if l.pos >= len(l.input) {
 		l.width = 0
 		return eof
 	}
-	r, l.width = utf8.DecodeRuneInString(l.input[l.pos:])
+	r, l.width = utf8.DecodeRune(l.input[l.pos:])
 	l.pos += l.width
 	return r
 
@@ -263,7 +741,7 @@ func (l *Lexer) acceptRun(validSet string) bool {
 
 // acceptString consumes a string
 func (l *Lexer) acceptString(str string) bool {
-	if strings.HasPrefix(l.input[l.pos:], str) {
+	if l.hasPrefix(str) {
 		for _, r := range str {
 			l.accept(string(r))
 		}
@@ -290,11 +768,7 @@ func (l *Lexer) accepted() bool {
 // ignore steps over the pending input before
 // this point.
 func (l *Lexer) ignore() {
-	l.start = l.pos
-}
-
-func (l *Lexer) ignoreN(n int) {
-	l.start += n
+	l.moveStart(l.pos)
 }
 
 // reset
@@ -308,17 +782,28 @@ func (l *Lexer) backup() {
 	l.pos -= l.width
 }
 
-// error returns an error token and terminates the scan
+// error records a diagnostic, returns an error token and terminates the scan
 // by passing back a nil pointer that will be the next
-// state, terminating l.run.
-func (l *Lexer) errorf(format string, args ...interface{}) stateFunc {
+// state, terminating l.run. The input consumed by the state is skipped
+// the next time the lexer runs.
+func (l *Lexer) errorf(code DiagnosticCode, format string, args ...interface{}) stateFunc {
 	// l.tokens <- Token{
 	// 	Error,
 	// 	fmt.Sprintf(format, args...),
 	// }
+	// the diagnostic is reported at the start of the token being lexed so
+	// that it lines up with the FilePosition of the token
+	d := Diagnostic{
+		FilePosition: l.startPosition(),
+		Message:      fmt.Sprintf(format, args...),
+		Code:         code,
+	}
+	l.diagnostics = append(l.diagnostics, d)
 	l.tokens = append(l.tokens, Token{
-		Type:  ErrorToken,
-		Value: fmt.Sprintf(format, args...),
+		Type:          ErrorToken,
+		Value:         d.Message,
+		FilePosition:  d.FilePosition,
+		NewlineBefore: l.newlineBefore,
 	})
 	return nil
 }
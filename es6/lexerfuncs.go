@@ -1,8 +1,16 @@
 package es6
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
 )
 
 // lexMux multiplexes the various states based on
@@ -16,66 +24,150 @@ import (
 //
 // CommonToken :: IdentifierName | Punctuator | NumericLiteral | StringLiteral | Template
 func lexInputElement(l *Lexer) stateFunc {
+	if l.fill(1) && l.input[l.pos] < utf8.RuneSelf {
+		switch asciiClasses[l.input[l.pos]] {
+		case whiteSpaceClass:
+			return lexWhiteSpace
+		case lineTerminatorClass:
+			return lexLineTerminator
+		case identifierStartClass:
+			return lexIdentifierName
+		case digitClass:
+			return lexNumericLiteral
+		case punctuatorClass:
+			return lexPunctuator
+		}
+	}
+	if state := triviaState(l); state != nil {
+		return state
+	}
 	switch {
-	case hasWhiteSpacePrefix(l):
-		return lexWhiteSpace
-	case hasLineTerminatorPrefix(l):
-		return lexLineTerminator
-	case strings.HasPrefix(l.input[l.pos:], "//"): // SingleLineComment
-		return lexSingleLineComment
-	case strings.HasPrefix(l.input[l.pos:], "/*"): // MultiLineComment
-		return lexMultiLineComment
-	case hasReservedWord(l, l.input[l.pos:]):
-		return lexReservedWord
 	case hasNumericLiteral(l):
 		return lexNumericLiteral
 	case hasPunctuator(l):
 		return lexPunctuator
 	case hasIdentifierNameStartPrefix(l): // IdentifierName
 		return lexIdentifierName
-	case strings.HasPrefix(l.input[l.pos:], "\""): // StringLiteral
+	case hasPrivateNamePrefix(l): // PrivateIdentifier
+		return lexPrivateName
+	case l.hasPrefix("\""): // StringLiteral
 		return lexStringLiteralDouble
-	case strings.HasPrefix(l.input[l.pos:], "'"): // StringLiteral
+	case l.hasPrefix("'"): // StringLiteral
 		return lexStringLiteralSingle
-	case strings.HasPrefix(l.input[l.pos:], "`"): // TemplateLiteral
+	case l.hasPrefix("`"): // TemplateLiteral
 		return lexTemplateLiteral
 	default:
 		switch l.goal {
 		case InputElementRegExp:
-			if strings.HasPrefix(l.input[l.pos:], "}") { // RightBracePunctuator
+			if l.hasPrefix("}") { // RightBracePunctuator
 				return lexRightBracePunctuator
 			}
-			if strings.HasPrefix(l.input[l.pos:], "/") {
+			if l.hasPrefix("/") {
 				return lexRegex
 			}
 		case InputElementRegExpOrTemplateTail:
-			if strings.HasPrefix(l.input[l.pos:], "}") { // TemplateSubstitutionTail
+			if l.hasPrefix("}") { // TemplateSubstitutionTail
 				return lexTemplateSubstitutionTail
 			}
-			if strings.HasPrefix(l.input[l.pos:], "/") {
+			if l.hasPrefix("/") {
 				return lexRegex
 			}
 		case InputElementTemplateTail:
-			if strings.HasPrefix(l.input[l.pos:], "}") { // TemplateSubstitutionTail
+			if hasDivPunctuator(l) {
+				return lexDivPunctuator
+			}
+			if l.hasPrefix("}") { // TemplateSubstitutionTail
 				return lexTemplateSubstitutionTail
 			}
 		case InputElementDiv:
 			if hasDivPunctuator(l) {
 				return lexDivPunctuator
 			}
-			if strings.HasPrefix(l.input[l.pos:], "}") { // RightBracePunctuator
+			if l.hasPrefix("}") { // RightBracePunctuator
 				return lexRightBracePunctuator
 			}
 		}
 	}
-	if l.pos != len(l.input) {
-		l.errorf("unexpected end of input")
+	if !l.atEOF() {
+		l.errorf(UnexpectedCharacter, "unexpected character %q", l.peek())
+		l.next()
 		return nil
 	}
+	if err := l.Err(); err != nil && !l.readErrReported {
+		l.readErrReported = true
+		l.errorf(ReadError, "failed to read input: %s", err)
+	}
 	l.emit(EOFToken)
 	return nil
 }
 
+// asciiClass is the input element that an ASCII character starts when it
+// does not depend on the goal or the characters after it
+type asciiClass uint8
+
+const (
+	otherClass asciiClass = iota
+	whiteSpaceClass
+	lineTerminatorClass
+	identifierStartClass
+	digitClass
+	punctuatorClass
+)
+
+// asciiClasses lets lexInputElement dispatch on the first byte of most
+// tokens, the characters left as otherClass are tried one input element at
+// a time. A . may start a NumericLiteral, / a comment or a
+// RegularExpressionLiteral, < and - an HTML-like comment and } depends on
+// the goal.
+var asciiClasses = func() (classes [utf8.RuneSelf]asciiClass) {
+	for _, c := range "\t\v\f " {
+		classes[c] = whiteSpaceClass
+	}
+	for _, c := range "\n\r" {
+		classes[c] = lineTerminatorClass
+	}
+	for c := 'a'; c <= 'z'; c++ {
+		classes[c] = identifierStartClass
+		classes[c-'a'+'A'] = identifierStartClass
+	}
+	classes['$'] = identifierStartClass
+	classes['_'] = identifierStartClass
+	for c := '0'; c <= '9'; c++ {
+		classes[c] = digitClass
+	}
+	for _, p := range punctuators {
+		classes[p[0]] = punctuatorClass
+	}
+	for _, c := range ".<-" {
+		classes[c] = otherClass
+	}
+	return classes
+}()
+
+// triviaState returns the state for lexing the WhiteSpace, LineTerminator
+// or Comment at the current position, these are the same for every goal. It
+// returns nil when the input does not start with one of them.
+func triviaState(l *Lexer) stateFunc {
+	switch {
+	case hasWhiteSpacePrefix(l):
+		return lexWhiteSpace
+	case hasLineTerminatorPrefix(l):
+		return lexLineTerminator
+	case l.hasPrefix("//"): // SingleLineComment
+		return lexSingleLineComment
+	case l.hasPrefix("/*"): // MultiLineComment
+		return lexMultiLineComment
+	case l.offset+l.start == 0 && l.hasPrefix("#!"): // HashbangComment
+		return lexHashbangComment
+	case !l.strict && l.hasPrefix("<!--"): // SingleLineHTMLOpenComment see B.1.3
+		return lexHTMLOpenComment
+	case !l.strict && (l.newlineBefore || !l.afterToken) && l.hasPrefix("-->"): // SingleLineHTMLCloseComment see B.1.3
+		return lexHTMLCloseComment
+	default:
+		return nil
+	}
+}
+
 // see 11.4
 
 //
@@ -84,32 +176,51 @@ func lexInputElement(l *Lexer) stateFunc {
 
 func lexMultiLineComment(l *Lexer) stateFunc {
 	l.acceptString("/*")
-	l.ignoreN(len("/*"))
-	var r rune
 	for {
 		if l.acceptString("*/") {
-			l.pos -= len("*/")
-			if l.pos >= l.start {
-				l.emit(MultiLineCommentToken)
-			}
-			l.ignoreN(len("*/"))
+			l.emitComment(MultiLineCommentToken, "/*", "*/")
 			return l.state
 		}
-		if r = l.next(); r == eof {
+		if l.next() == eof {
 			break
 		}
 	}
-	return l.errorf("no multi line comment terminator \"*/\"")
+	return l.errorf(UnterminatedComment, "no multi line comment terminator \"*/\"")
 }
 
+// lexSingleLineComment consumes a comment up to but not including the
+// LineTerminator that ends it, the LineTerminator is lexed as its own token
 func lexSingleLineComment(l *Lexer) stateFunc {
-	l.acceptString("//")
-	l.ignore()
+	return lexCommentLine(l, "//", SingleLineCommentToken)
+}
+
+// lexHashbangComment consumes the #! line at the start of the input, it is
+// allowed in both scripts and modules
+func lexHashbangComment(l *Lexer) stateFunc {
+	return lexCommentLine(l, "#!", HashbangCommentToken)
+}
+
+// lexHTMLOpenComment consumes a <!-- comment, these are only recognised in
+// sloppy mode scripts
+func lexHTMLOpenComment(l *Lexer) stateFunc {
+	return lexCommentLine(l, "<!--", SingleLineCommentToken)
+}
+
+// lexHTMLCloseComment consumes a --> comment at the start of a line, these
+// are only recognised in sloppy mode scripts
+func lexHTMLCloseComment(l *Lexer) stateFunc {
+	return lexCommentLine(l, "-->", SingleLineCommentToken)
+}
+
+// lexCommentLine consumes open and the rest of the line after it, it is
+// emitted as a comment of type typ
+func lexCommentLine(l *Lexer, open string, typ TokenType) stateFunc {
+	l.acceptString(open)
 	for {
-		if strings.HasPrefix(l.input[l.pos:], "\n") || l.next() == eof {
-			l.emit(SingleLineCommentToken)
-			l.accept("\n")
-			l.ignore()
+		r := l.next()
+		if r == eof || isLineTerminator(r) {
+			l.backup()
+			l.emitComment(typ, open, "")
 			return l.state
 		}
 	}
@@ -119,32 +230,120 @@ func lexSingleLineComment(l *Lexer) stateFunc {
 // identifier
 //
 
+// isIdentifierStart reports whether r may begin an IdentifierName
+//
+// IdentifierStart :: UnicodeIDStart || $ || _ || \ UnicodeEscapeSequence
 func isIdentifierStart(r rune) bool {
-	return r == '$' || r == '_' ||
-		(unicode.IsOneOf([]*unicode.RangeTable{unicode.L, unicode.Nd, unicode.Other_ID_Start}, r) &&
-			!(unicode.IsOneOf([]*unicode.RangeTable{unicode.Pattern_Syntax, unicode.Pattern_White_Space}, r)))
+	return r == '$' || r == '_' || isUnicodeIDStart(r)
 }
 
+// isIdentifierPart reports whether r may continue an IdentifierName
+//
+// IdentifierPart :: UnicodeIDContinue || $ || _ || \ UnicodeEscapeSequence || <ZWNJ> || <ZWJ>
 func isIdentifierPart(r rune) bool {
-	return r == '$' || r == '_' || isIdentifierStart(r) || unicode.IsOneOf([]*unicode.RangeTable{unicode.L, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc, unicode.Other_ID_Start}, r)
+	return r == '$' || r == '_' || r == '\u200C' || r == '\u200D' || isUnicodeIDContinue(r)
 }
-func hasIdentifierNameStartPrefix(l *Lexer) bool {
-	return isIdentifierStart(l.peek())
+
+// isUnicodeIDStart reports whether r has the Unicode property ID_Start,
+// which is derived as: L + Nl + Other_ID_Start - Pattern_Syntax - Pattern_White_Space
+func isUnicodeIDStart(r rune) bool {
+	if r < utf8.RuneSelf {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z'
+	}
+	return unicode.IsOneOf(idStartTables, r) && !unicode.IsOneOf(patternTables, r)
+}
+
+// isUnicodeIDContinue reports whether r has the Unicode property ID_Continue,
+// which is derived as: ID_Start + Mn + Mc + Nd + Pc + Other_ID_Continue - Pattern_Syntax - Pattern_White_Space
+func isUnicodeIDContinue(r rune) bool {
+	if r < utf8.RuneSelf {
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '_'
+	}
+	return unicode.IsOneOf(idContinueTables, r) && !unicode.IsOneOf(patternTables, r)
 }
 
-func hasIdentifierNameContinuePrefix(l *Lexer) bool {
-	return isIdentifierPart(l.peek())
+var (
+	idStartTables    = []*unicode.RangeTable{unicode.L, unicode.Nl, unicode.Other_ID_Start}
+	idContinueTables = []*unicode.RangeTable{unicode.L, unicode.Nl, unicode.Other_ID_Start, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc, unicode.Other_ID_Continue}
+	patternTables    = []*unicode.RangeTable{unicode.Pattern_Syntax, unicode.Pattern_White_Space}
+)
+
+func hasIdentifierNameStartPrefix(l *Lexer) bool {
+	return isIdentifierStart(l.peek()) || l.hasPrefix(`\u`)
 }
 
+// lexIdentifierName consumes an IdentifierName and then classifies it as a
+// ReservedWord, a contextual keyword or a plain identifier. When the name
+// contains unicode escape sequences the decoded name is set as the token's
+// Cooked value, an escaped name is never recognised as a keyword
 func lexIdentifierName(l *Lexer) stateFunc {
-	l.next()
-	for {
-		if !hasIdentifierNameContinuePrefix(l) {
-			l.emit(IdentifierNameToken)
-			return l.state
+	name, escaped := scanIdentifierName(l, 0)
+	if escaped {
+		l.emitToken(Token{Type: IdentifierNameToken, Cooked: name})
+		return l.state
+	}
+	// the name is the source of the token so it is only copied once
+	switch {
+	case l.isReservedWord(name):
+		l.emitToken(Token{Type: ReservedWordToken, Source: name})
+	case isContextualWord(name):
+		l.emitToken(Token{Type: IdentifierNameToken, Source: name, Cooked: name, Contextual: true})
+	default:
+		l.emitToken(Token{Type: IdentifierNameToken, Source: name, Cooked: name})
+	}
+	return l.state
+}
+
+func hasPrivateNamePrefix(l *Lexer) bool {
+	defer l.reset()
+	return l.accept("#") && hasIdentifierNameStartPrefix(l)
+}
+
+// lexPrivateName consumes a PrivateIdentifier, its name including the # is
+// set as the token's Cooked value
+func lexPrivateName(l *Lexer) stateFunc {
+	l.accept("#")
+	name, _ := scanIdentifierName(l, len("#"))
+	l.requires(ES2022, "private names")
+	l.emitToken(Token{Type: PrivateNameToken, Cooked: "#" + name})
+	return l.state
+}
+
+// scanIdentifierName consumes an IdentifierName that starts begin bytes
+// after start and returns it with its escape sequences decoded
+func scanIdentifierName(l *Lexer, begin int) (name string, escaped bool) {
+	var decoded strings.Builder
+	for first := true; ; first = false {
+		if l.hasPrefix(`\u`) {
+			if !escaped {
+				escaped = true
+				decoded.Write(l.input[l.start+begin : l.pos])
+			}
+			l.acceptString(`\u`)
+			r, err := lexUnicodeEscapeSequence(l)
+			if err == nil && !(first && isIdentifierStart(r) || !first && isIdentifierPart(r)) {
+				err = fmt.Errorf("escaped character %U is not allowed in an identifier", r)
+			}
+			if err != nil {
+				l.errorf(InvalidEscapeSequence, "%s", err)
+				continue
+			}
+			decoded.WriteRune(r)
+			continue
+		}
+		r := l.next()
+		if first && !isIdentifierStart(r) || !first && !isIdentifierPart(r) {
+			l.backup()
+			break
+		}
+		if escaped {
+			decoded.WriteRune(r)
 		}
-		l.next()
 	}
+	if escaped {
+		return decoded.String(), true
+	}
+	return string(l.input[l.start+begin : l.pos]), false
 }
 
 //
@@ -153,16 +352,22 @@ func lexIdentifierName(l *Lexer) stateFunc {
 
 var lineTerminators = "\u000A\u000D\u2028\u2029"
 
+func isLineTerminator(r rune) bool {
+	return r == '\n' || r == '\r' || r == '\u2028' || r == '\u2029'
+}
+
 func hasLineTerminatorPrefix(l *Lexer) bool {
 	defer l.reset()
 	return l.accept(lineTerminators)
 }
 
+// lexLineTerminator consumes a LineTerminatorSequence, a carriage return
+// followed by a line feed is a single LineTerminator
 func lexLineTerminator(l *Lexer) stateFunc {
-	l.accept(lineTerminators)
+	if !l.acceptString("\r\n") {
+		l.accept(lineTerminators)
+	}
 	l.emit(LineTerminatorToken)
-	l.line++
-	l.column = 0
 	return l.state
 }
 
@@ -176,53 +381,132 @@ const decimalDigits = "0123456789"
 
 func hasNumericLiteral(l *Lexer) bool {
 	defer l.reset()
-	l.accept("-")
-	return l.accept("123456789") || (l.accept(".") && l.accept("0123456789")) || (l.accept("0") && (l.accept("oOxXbB") || !hasIdentifierNameStartPrefix(l)))
+	return l.accept(decimalDigits) || (l.accept(".") && l.accept(decimalDigits))
 }
 
-// lexNumericLiteral inspired by Rob Pike's talk
+// lexNumericLiteral inspired by Rob Pike's talk. It emits the literal with
+// its value decoded into Number. A sign is not part of the literal, it is
+// lexed as a punctuator and left to the parser as a unary operator
 func lexNumericLiteral(l *Lexer) stateFunc {
-
-	// Next thing mustn't be alphanumeric.
-	mustNotHaveNextAlpha := func(l *Lexer) stateFunc {
-		if unicode.IsLetter(l.peek()) {
-			l.next()
-			return l.errorf("bad number syntax: %q", l.input[l.start:l.pos])
+	// a BigInt literal is an integer without a leading zero
+	bigInt := true
+	if l.accept("0") {
+		switch {
+		case l.accept("xX"):
+			return lexNonDecimalIntegerLiteral(l, 16, "0123456789abcdefABCDEF")
+		case l.accept("oO"):
+			return lexNonDecimalIntegerLiteral(l, 8, decimalDigits[:8])
+		case l.accept("bB"):
+			return lexNonDecimalIntegerLiteral(l, 2, "01")
+		case l.acceptRun(decimalDigits):
+			// see B.1.1
+			digits := string(l.input[l.start+1 : l.pos])
+			if strings.Trim(digits, decimalDigits[:8]) == "" {
+				if l.strict {
+					l.errorf(LegacyNumericLiteral, "legacy octal literals are not allowed in strict mode")
+				}
+				value, _ := strconv.ParseUint(digits, 8, 64)
+				return emitNumericLiteral(l, Token{Number: float64(value)})
+			}
+			if l.strict {
+				l.errorf(LegacyNumericLiteral, "decimal literals with a leading zero are not allowed in strict mode")
+			}
+			bigInt = false
 		}
-		l.emit(NumericLiteralToken)
-		return l.state
+	} else {
+		acceptDigits(l, decimalDigits)
 	}
 
-	// Optional leading sign.
-	l.accept("-")
-	// Is it hex?
-	if l.accept("0") {
-		if l.accept("xX") {
-			l.acceptRun("0123456789abcdefABCDEF")
-			return mustNotHaveNextAlpha(l)
-		} else if l.accept("oO") { // Is it octal?
-			l.acceptRun(decimalDigits[:8])
-			return mustNotHaveNextAlpha(l)
-		} else if l.accept("bB") { // Is it bin?
-			l.acceptRun("01")
-			return mustNotHaveNextAlpha(l)
+	if l.accept(".") {
+		bigInt = false
+		acceptDigits(l, decimalDigits)
+	}
+
+	if l.accept("eE") {
+		bigInt = false
+		l.accept("+-")
+		if !acceptDigits(l, decimalDigits) {
+			return badNumericLiteral(l)
 		}
 	}
 
-	if l.accept(decimalDigits[1:]) {
-		l.acceptRun(decimalDigits)
+	digits := strings.Replace(string(l.input[l.start:l.pos]), "_", "", -1)
+	if bigInt && l.accept("n") {
+		value, _ := new(big.Int).SetString(digits, 10)
+		return emitBigIntLiteral(l, value)
 	}
 
-	if l.accept(".") {
-		l.acceptRun(decimalDigits)
+	// ParseFloat rounds to the nearest value like ToNumber does and returns
+	// ±Inf with a range error for values too large to represent
+	value, err := strconv.ParseFloat(digits, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return badNumericLiteral(l)
 	}
+	return emitNumericLiteral(l, Token{Number: value})
+}
 
-	if l.accepted() && l.accept("eE") {
-		l.accept("+-")
-		l.acceptRun(decimalDigits)
+// acceptDigits consumes a run of digits, a NumericLiteralSeparator is only
+// consumed when it is between two digits
+func acceptDigits(l *Lexer, digits string) bool {
+	if !l.acceptRun(digits) {
+		return false
+	}
+	for {
+		l.fill(len("_0"))
+		if l.pos == len(l.input) || l.input[l.pos] != '_' || l.pos+1 == len(l.input) || strings.IndexByte(digits, l.input[l.pos+1]) < 0 {
+			return true
+		}
+		l.accept("_")
+		l.acceptRun(digits)
+	}
+}
+
+// lexNonDecimalIntegerLiteral consumes the digits of a hex, octal or binary
+// literal after its prefix
+func lexNonDecimalIntegerLiteral(l *Lexer, base int, digits string) stateFunc {
+	if !acceptDigits(l, digits) {
+		return badNumericLiteral(l)
+	}
+	value, ok := new(big.Int).SetString(strings.Replace(string(l.input[l.start+len("0x"):l.pos]), "_", "", -1), base)
+	if !ok {
+		return badNumericLiteral(l)
+	}
+	if l.accept("n") {
+		return emitBigIntLiteral(l, value)
+	}
+	f, _ := new(big.Float).SetInt(value).Float64()
+	return emitNumericLiteral(l, Token{Number: f})
+}
+
+func emitBigIntLiteral(l *Lexer, value *big.Int) stateFunc {
+	f, _ := new(big.Float).SetInt(value).Float64()
+	return emitNumericLiteral(l, Token{Number: f, BigInt: value})
+}
+
+// emitNumericLiteral emits the literal unless it is immediately followed by
+// an IdentifierStart or a DecimalDigit (see 11.8.3)
+func emitNumericLiteral(l *Lexer, tok Token) stateFunc {
+	if r := l.peek(); isIdentifierStart(r) || strings.ContainsRune(decimalDigits, r) {
+		return badNumericLiteral(l)
+	}
+	if bytes.IndexByte(l.input[l.start:l.pos], '_') >= 0 {
+		l.requires(ES2021, "numeric separators")
 	}
+	if tok.BigInt != nil {
+		l.requires(ES2020, "BigInt literals")
+	}
+	tok.Type = NumericLiteralToken
+	l.emitToken(tok)
+	return l.state
+}
 
-	return mustNotHaveNextAlpha(l)
+// badNumericLiteral reports the literal along with the IdentifierPart
+// characters that follow it, lexing resumes after them
+func badNumericLiteral(l *Lexer) stateFunc {
+	for isIdentifierPart(l.peek()) {
+		l.next()
+	}
+	return l.errorf(InvalidNumericLiteral, "bad number syntax: %q", l.input[l.start:l.pos])
 }
 
 //
@@ -231,20 +515,67 @@ func lexNumericLiteral(l *Lexer) stateFunc {
 
 var punctuators = []string{
 	"{", "(", ")", ";", "]", "[", ",",
+	"**=", "**", "?.", "??=", "??", "||=", "&&=",
 	">>>=", "<<=", "!==", "===", ">>>", "...", ".", ">>=", ">=",
 	"%=", "*=", "-=", "<=", "&=", "==", "!=", "|=",
 	"^=", "+=", "<<", "||", "&&", "++", "--", "=>",
 	">>", "-", "&", "|", "^", "!", "~", "%",
 	"*", "?", ":", "=", "+", ">", "<"}
 
+// punctuatorsByByte are the punctuators grouped by their first byte, longest
+// first so that the first one that matches is the longest
+var punctuatorsByByte = func() (table [utf8.RuneSelf][]string) {
+	for _, p := range punctuators {
+		table[p[0]] = append(table[p[0]], p)
+	}
+	for _, ps := range table {
+		sort.SliceStable(ps, func(i, j int) bool { return len(ps[i]) > len(ps[j]) })
+	}
+	return table
+}()
+
+// maxPunctuatorLength is the length of >>>=
+const maxPunctuatorLength = 4
+
+// matchPunctuator returns the longest punctuator at the start of the unread
+// input or "" when there is none
+func matchPunctuator(l *Lexer) string {
+	l.fill(maxPunctuatorLength)
+	if l.pos >= len(l.input) || l.input[l.pos] >= utf8.RuneSelf {
+		return ""
+	}
+	for _, p := range punctuatorsByByte[l.input[l.pos]] {
+		if l.hasPrefix(p) {
+			return p
+		}
+	}
+	return ""
+}
+
 func hasPunctuator(l *Lexer) bool {
-	defer l.reset()
-	return l.acceptAnyString(punctuators)
+	return matchPunctuator(l) != ""
+}
+
+// punctuatorEditions are the editions that added punctuators after ES2015
+var punctuatorEditions = map[string]Edition{
+	"**": ES2016, "**=": ES2016,
+	"?.": ES2020, "??": ES2020,
+	"??=": ES2021, "||=": ES2021, "&&=": ES2021,
 }
 
 func lexPunctuator(l *Lexer) stateFunc {
-	l.acceptAnyString(punctuators)
-	l.emit(PunctuatorToken)
+	punctuator := matchPunctuator(l)
+	l.pos += len(punctuator)
+	// ?. [lookahead ∉ DecimalDigit] so that a?.5:b is a conditional
+	if punctuator == "?." && strings.ContainsRune(decimalDigits, l.peek()) {
+		l.pos -= len(".")
+		punctuator = "?"
+	}
+	if edition, ok := punctuatorEditions[punctuator]; ok && !l.supports(edition) {
+		l.requires(edition, fmt.Sprintf("%q", punctuator))
+	}
+	// punctuator is a constant so the source is not copied from the input
+	l.emitToken(Token{Type: PunctuatorToken, Source: punctuator})
 	return l.state
 }
 
@@ -270,28 +601,61 @@ func lexRightBracePunctuator(l *Lexer) stateFunc {
 // regex
 //
 
+// lexRegex consumes a RegularExpressionLiteral. The body is scanned as a
+// RegularExpressionBody so a "/" inside of a RegularExpressionClass or
+// following a backslash does not end the literal. Flags that are not one
+// of "gimuy" or that are repeated are each reported as an error at the
+// position of the flag.
+//
+// RegularExpressionLiteral :: / RegularExpressionBody / RegularExpressionFlags
 func lexRegex(l *Lexer) stateFunc {
 	l.accept("/")
 
-	l.acceptSourceCharacterRunExcept(lineTerminators + "/")
-	if !l.accept("/") {
-		l.errorf("regex did not close with '/' ")
-		return nil
+	inClass := false
+body:
+	for {
+		r := l.next()
+		switch {
+		case r == eof || isLineTerminator(r):
+			return l.errorf(UnterminatedRegularExpression, "regex did not close with '/' ")
+		case r == '\\': // RegularExpressionBackslashSequence
+			if r = l.next(); r == eof || isLineTerminator(r) {
+				return l.errorf(UnterminatedRegularExpression, "regex did not close with '/' ")
+			}
+		case r == '[': // RegularExpressionClass
+			inClass = true
+		case r == ']':
+			inClass = false
+		case r == '/' && !inClass:
+			break body
+		}
 	}
+	pattern := string(l.input[l.start+1 : l.pos-1])
 
+	// indexes into input are only stable relative to start as the window
+	// can slide while the flags are read
+	flagsStart := l.pos - l.start
 	for {
-		r := l.next()
-		if !isIdentifierPart(r) {
-			l.backup()
+		r := l.peek()
+		if !isIdentifierPart(r) && r != '\\' {
 			break
 		}
+		switch {
+		case !strings.ContainsRune(regexFlags, r):
+			l.errorf(InvalidRegularExpressionFlag, "invalid regular expression flag %q", r)
+		case bytes.ContainsRune(l.input[l.start+flagsStart:l.pos], r):
+			l.errorf(InvalidRegularExpressionFlag, "duplicate regular expression flag %q", r)
+		}
+		l.next()
 	}
 
-	l.emit(RegExToken)
-
-	return nil
+	l.emitToken(Token{Type: RegExToken, Pattern: pattern, Flags: string(l.input[l.start+flagsStart : l.pos])})
+	return l.state
 }
 
+// regexFlags are the valid RegularExpressionFlags
+const regexFlags = "gimuy"
+
 //
 // ReservedWord
 //
@@ -302,91 +666,179 @@ var currentReservedWords = []string{
 	"catch", "export", "new", "void",
 	"class", "extends", "return", "while",
 	"const", "finally", "super", "with",
-	"continue", "for", "switch", "yield",
+	"continue", "for", "switch",
 	"debugger", "function", "this", "default",
 	"if", "throw", "delete", "import", "try"}
-var futureReservedWords = []string{"enum", "await"}
+var futureReservedWords = []string{"enum"}
 var futureResdervedWordsStrict = []string{"implements", "package", "protected", "interface", "private", "public"}
 var literals = []string{"null", "true", "false"}
 
-func hasReservedWord(l *Lexer, str string) bool {
-	for _, word := range l.reservedWords {
-		if strings.HasPrefix(str, word) {
-			return true
-		}
-	}
-	return false
-}
+// contextualWords are lexed as IdentifierNames with Contextual set, whether
+// they are keywords or identifiers depends on where they appear so that is
+// left to the parser. let, static and yield are also reserved in strict mode
+// code and await in module code
+var contextualWords = []string{
+	"let", "static", "yield", "await",
+	"of", "get", "set", "as", "from", "target"}
 
-func lexReservedWord(l *Lexer) stateFunc {
-	l.acceptAnyString(l.reservedWords)
-	l.emit(ReservedWordToken)
-	return l.state
+// keywordKind is how a word in keywords is lexed
+type keywordKind uint8
+
+const (
+	notKeyword keywordKind = iota
+	reservedKeyword
+	strictReservedKeyword
+	contextualKeyword
+)
+
+const keywordTableSize = 128
+
+type keyword struct {
+	word string
+	kind keywordKind
 }
 
-//
-// helper code
-//
+// keywords is a perfect hash table of the ReservedWords and contextual words
+// so that classifying an IdentifierName is a single string compare
+var keywords = func() (table [keywordTableSize]keyword) {
+	add := func(kind keywordKind, words []string) {
+		for _, word := range words {
+			h := keywordHash(word)
+			if table[h].word != "" {
+				panic(fmt.Sprintf("keywords %q and %q have the same hash", table[h].word, word))
+			}
+			table[h] = keyword{word: word, kind: kind}
+		}
+	}
+	add(reservedKeyword, currentReservedWords)
+	add(reservedKeyword, futureReservedWords)
+	add(reservedKeyword, literals)
+	add(strictReservedKeyword, futureResdervedWordsStrict)
+	add(contextualKeyword, contextualWords)
+	return table
+}()
 
-// copied from: https://gobyexample.com/sorting-by-functions
-type keywordSorter []string
+// keywordHash is only collision free for the words in keywords, it must be
+// changed when one is added that has the same hash as another
+func keywordHash(word string) int {
+	return (int(word[0])*16 + int(word[1])*7 + int(word[len(word)-1])*55 + len(word)) % keywordTableSize
+}
 
-func (s keywordSorter) Len() int {
-	return len(s)
+// keywordKindOf returns the kind of word in keywords or notKeyword
+func keywordKindOf(word string) keywordKind {
+	if len(word) < 2 || len(word) > len("instanceof") {
+		return notKeyword
+	}
+	if k := keywords[keywordHash(word)]; k.word == word {
+		return k.kind
+	}
+	return notKeyword
 }
-func (s keywordSorter) Swap(i, j int) {
-	s[i], s[j] = s[j], s[i]
+
+// isReservedWord reports whether word is a ReservedWord in the current mode
+func (l *Lexer) isReservedWord(word string) bool {
+	switch keywordKindOf(word) {
+	case reservedKeyword:
+		return true
+	case strictReservedKeyword:
+		return l.strict
+	default:
+		return false
+	}
 }
-func (s keywordSorter) Less(i, j int) bool {
-	return len(s[i]) > len(s[j]) // sorts reverse order
+
+func isContextualWord(word string) bool {
+	return keywordKindOf(word) == contextualKeyword
 }
 
 //
 // template literals
 //
 
+// lexTemplateLiteral consumes a NoSubstitutionTemplate or a TemplateHead
 func lexTemplateLiteral(l *Lexer) stateFunc {
-	l.accept("`")
-	var r rune
-	for {
-		if strings.HasPrefix(l.input[l.pos:], "${") {
-			l.acceptString("${")
-			l.emit(TemplateHeadToken)
-			return l.state
-		}
-		if strings.HasPrefix(l.input[l.pos:], "`") {
-			l.accept("`")
-			l.emit(NoSubstitutionTemplateToken)
-			return l.state
-		}
-		if r = l.next(); r == eof {
-			l.errorf("did not reach end of template literal reached eof")
-			return nil
-		}
-	}
+	return lexTemplate(l, "`", NoSubstitutionTemplateToken, TemplateHeadToken,
+		"did not reach end of template literal reached eof")
 }
 
+// lexTemplateSubstitutionTail consumes a TemplateMiddle or a TemplateTail
 func lexTemplateSubstitutionTail(l *Lexer) stateFunc {
-	l.accept("}")
-	var r rune
+	return lexTemplate(l, "}", TemplateTailToken, TemplateMiddleToken,
+		"did not reach TemplateMiddle or TemplateTail but reached eof")
+}
+
+// lexTemplate consumes open and the TemplateCharacters after it. The token
+// is emitted as tail when it ends with a backtick and as head when it ends
+// with a substitution
+func lexTemplate(l *Lexer, open string, tail, head TokenType, eofMessage string) stateFunc {
+	l.acceptString(open)
+	var (
+		value  cooked
+		escErr error
+		// indexes into input are only stable relative to start
+		begin = l.pos - l.start
+	)
 	for {
-		if strings.HasPrefix(l.input[l.pos:], "${") {
-			l.acceptString("${")
-			l.emit(TemplateMiddleToken)
-			return l.state
-		}
-		if strings.HasPrefix(l.input[l.pos:], "`") {
-			l.accept("`")
-			l.emit(TemplateTailToken)
-			return l.state
+		switch {
+		case l.hasPrefix("`"):
+			return emitTemplate(l, tail, "`", begin, value, escErr)
+		case l.hasPrefix("${"):
+			return emitTemplate(l, head, "${", begin, value, escErr)
 		}
-		if r = l.next(); r == eof {
-			l.errorf("did not reach TemplateMiddle or TemplateTail but reached eof")
+		switch r := l.next(); r {
+		case eof:
+			l.errorf(UnterminatedTemplateLiteral, "%s", eofMessage)
 			return nil
+		case '\\':
+			if err := lexTemplateEscapeSequence(l, &value); err != nil && escErr == nil {
+				escErr = err
+			}
+		case '\r':
+			l.accept("\n")
+			value.appendRune('\n')
+		default:
+			value.appendRune(r)
 		}
 	}
 }
 
+// emitTemplate consumes end and emits the template with its raw value, the
+// input from begin, and its cooked value (see 11.8.6.1)
+func emitTemplate(l *Lexer, typ TokenType, end string, begin int, value cooked, escErr error) stateFunc {
+	raw := crlf.Replace(string(l.input[l.start+begin : l.pos]))
+	l.acceptString(end)
+	tok := Token{Type: typ, Raw: raw, CookedErr: escErr}
+	if escErr == nil {
+		tok.Cooked = value.String()
+	}
+	l.emitToken(tok)
+	return l.state
+}
+
+// crlf replaces a carriage return, alone or followed by a line feed, with a
+// line feed as the TRV of a LineTerminatorSequence is a line feed
+var crlf = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// lexTemplateEscapeSequence is lexEscapeSequence for template literals,
+// which never allow legacy octal escapes or \8 and \9
+func lexTemplateEscapeSequence(l *Lexer, c *cooked) error {
+	r := l.peek()
+	if !strings.ContainsRune(decimalDigits, r) {
+		return lexEscapeSequence(l, c)
+	}
+	l.next()
+	switch {
+	// 0 [lookahead ∉ DecimalDigit]
+	case r == '0' && !strings.ContainsRune(decimalDigits, l.peek()):
+		c.appendRune(0)
+		return nil
+	case r == '8' || r == '9':
+		return fmt.Errorf("\\%c is not allowed in template literals", r)
+	default:
+		return fmt.Errorf("octal escape sequences are not allowed in template literals")
+	}
+}
+
 //
 // WhiteSpace
 //
@@ -413,64 +865,208 @@ func lexWhiteSpace(l *Lexer) stateFunc {
 // lexStringLiteralDouble consumes a string literal surounded by
 // a double quotation marks
 func lexStringLiteralDouble(l *Lexer) stateFunc {
-	l.accept("\"")
-	var r rune
-	for {
-		if strings.HasPrefix(l.input[l.pos:], "\"") {
-			break
-		}
-		if r = l.next(); r == eof {
-			l.errorf("did not reach end of string literal reached eof")
-			break
-		}
-	}
-	l.accept("\"")
-	l.emit(StringLiteralToken)
-	return l.state
+	return lexStringLiteral(l, '"')
 }
 
 // lexStringLiteralSingle consumes a string literal surounded by
 // a single quotation marks
 func lexStringLiteralSingle(l *Lexer) stateFunc {
-	l.accept("'")
-	var r rune
+	return lexStringLiteral(l, '\'')
+}
+
+// lexStringLiteral consumes a string literal surounded by quote and emits
+// it with its cooked value. An invalid escape sequence is reported before
+// the literal so the rest of the input can still be lexed, as is a LF or CR
+// that is not escaped, which ends the literal
+func lexStringLiteral(l *Lexer, quote rune) stateFunc {
+	l.next()
+	var (
+		value  cooked
+		escErr error
+	)
 	for {
-		if l.accept("'") {
+		r := l.next()
+		switch r {
+		case quote:
+			if escErr != nil {
+				l.errorf(InvalidEscapeSequence, "%s", escErr)
+			}
+			l.emitToken(Token{Type: StringLiteralToken, Cooked: value.String()})
+			return l.state
+		case eof:
+			l.errorf(UnterminatedStringLiteral, "did not reach end of string literal reached eof")
+			l.emitToken(Token{Type: StringLiteralToken, Cooked: value.String()})
+			return l.state
+		case '\n', '\r':
+			// the literal ends before the line terminator so an unclosed
+			// quote does not swallow the rest of the input
+			l.backup()
+			l.errorf(UnterminatedStringLiteral, "did not reach end of string literal before the end of the line")
+			l.emitToken(Token{Type: StringLiteralToken, Cooked: value.String()})
+			return l.state
+		case '\\':
+			if err := lexEscapeSequence(l, &value); err != nil && escErr == nil {
+				escErr = err
+			}
+		default:
+			value.appendRune(r)
+		}
+	}
+}
+
+// cooked accumulates the value of a literal as UTF-16 code units, the
+// representation used by ECMAScript strings, so that an escaped surrogate
+// pair such as "\uD83D\uDE00" decodes to a single code point
+type cooked []uint16
+
+func (c *cooked) appendRune(r rune) {
+	if r >= 0x10000 {
+		r1, r2 := utf16.EncodeRune(r)
+		*c = append(*c, uint16(r1), uint16(r2))
+		return
+	}
+	*c = append(*c, uint16(r))
+}
+
+func (c cooked) String() string {
+	return string(utf16.Decode(c))
+}
+
+// lexEscapeSequence consumes the EscapeSequence or LineContinuation that
+// follows a backslash and appends its value to c
+//
+// EscapeSequence :: CharacterEscapeSequence || 0 [lookahead ∉ DecimalDigit] || HexEscapeSequence || UnicodeEscapeSequence
+// LineContinuation :: \ LineTerminatorSequence
+func lexEscapeSequence(l *Lexer, c *cooked) error {
+	r := l.next()
+	switch r {
+	case eof:
+		return fmt.Errorf("unterminated escape sequence")
+	// SingleEscapeCharacter :: ' " \ b f n r t v
+	case 'b':
+		c.appendRune('\b')
+	case 'f':
+		c.appendRune('\f')
+	case 'n':
+		c.appendRune('\n')
+	case 'r':
+		c.appendRune('\r')
+	case 't':
+		c.appendRune('\t')
+	case 'v':
+		c.appendRune('\v')
+	// LineContinuation contributes nothing to the value
+	case '\r':
+		l.accept("\n")
+	case '\n', '\u2028', '\u2029':
+	// HexEscapeSequence :: x HexDigit HexDigit
+	case 'x':
+		v, ok := lexHexDigits(l, 2)
+		if !ok {
+			return fmt.Errorf("invalid hexadecimal escape sequence")
+		}
+		c.appendRune(v)
+	case 'u':
+		v, err := lexUnicodeEscapeSequence(l)
+		if err != nil {
+			return err
+		}
+		c.appendRune(v)
+	case '0', '1', '2', '3', '4', '5', '6', '7':
+		// 0 [lookahead ∉ DecimalDigit]
+		if r == '0' && !strings.ContainsRune(decimalDigits, l.peek()) {
+			c.appendRune(0)
+			return nil
+		}
+		if l.strict {
+			return fmt.Errorf("octal escape sequences are not allowed in strict mode")
+		}
+		c.appendRune(lexLegacyOctalEscapeSequence(l, r))
+	case '8', '9':
+		if l.strict {
+			return fmt.Errorf("\\%c is not allowed in strict mode", r)
+		}
+		c.appendRune(r)
+	// NonEscapeCharacter
+	default:
+		c.appendRune(r)
+	}
+	return nil
+}
+
+// lexUnicodeEscapeSequence consumes the digits of a UnicodeEscapeSequence
+// that follow "\u" and returns the code point or code unit it encodes
+//
+// UnicodeEscapeSequence :: u Hex4Digits || u{ HexDigits }
+func lexUnicodeEscapeSequence(l *Lexer) (rune, error) {
+	if !l.accept("{") {
+		v, ok := lexHexDigits(l, 4)
+		if !ok {
+			return 0, fmt.Errorf("invalid unicode escape sequence")
+		}
+		return v, nil
+	}
+	var (
+		v rune
+		n int
+	)
+	for ; ; n++ {
+		d, ok := hexValue(l.peek())
+		if !ok {
 			break
 		}
-		if r = l.next(); r == eof {
-			l.errorf("did not reach end of string literal reached eof")
+		l.next()
+		if v = v*16 + d; v > unicode.MaxRune {
+			return 0, fmt.Errorf("unicode escape sequence is out of range")
+		}
+	}
+	if n == 0 || !l.accept("}") {
+		return 0, fmt.Errorf("invalid unicode escape sequence")
+	}
+	return v, nil
+}
+
+// lexLegacyOctalEscapeSequence consumes the rest of a LegacyOctalEscapeSequence
+// begining with the octal digit first (See B.1.2)
+func lexLegacyOctalEscapeSequence(l *Lexer, first rune) rune {
+	v := first - '0'
+	digits := 2
+	if first > '3' {
+		digits = 1
+	}
+	for i := 0; i < digits; i++ {
+		r := l.peek()
+		if r < '0' || r > '7' {
 			break
 		}
+		l.next()
+		v = v*8 + r - '0'
 	}
-	l.accept("'")
-	l.emit(StringLiteralToken)
-	return l.state
+	return v
 }
 
-// // EscapeSequence :: CharacterEscapeSequence || 0 [lookahead ∉ DecimalDigit] || HexEscapeSequence || UnicodeEscapeSequence
-// func lexEscapeSequence(l *Lexer) {
-// 	// CharacterEscapeSequence
-// 	if l.accept("\\") {
-// 		// SingleEscapeCharacter :: ' " \ b f n r t v
-// 		if l.accept("'\"\\bfnrtv") {
-// 			return
-// 		}
-//
-// 		// 0 [lookahead ∉ DecimalDigit]
-// 		if l.accept("0") && strings.ContainsRune(decimalDigits, l.peek()) {
-// 			return
-// 		}
-//
-// 		// HexEscapeSequence :: x HexDigit HexDigit
-// 		if l.accept("x") && l.acceptRunN(decimalDigits+"abcdefABCDEF", 2) {
-// 			return
-// 		}
-//
-// 		// UnicodeEscapeSequence :: u Hex4Digits
-// 		if l.accept("u") &&
-// 			(l.acceptRunN(decimalDigits+"abcdefABCDEF", 4) || l.accept("{") && l.acceptRunN(decimalDigits+"abcdefABCDEF", 4) && l.accept("}")) {
-// 			return
-// 		}
-// 	}
-// }
+// lexHexDigits consumes exactly n hex digits and returns their value
+func lexHexDigits(l *Lexer, n int) (rune, bool) {
+	var v rune
+	for i := 0; i < n; i++ {
+		d, ok := hexValue(l.peek())
+		if !ok {
+			return 0, false
+		}
+		l.next()
+		v = v*16 + d
+	}
+	return v, true
+}
+
+func hexValue(r rune) (rune, bool) {
+	switch {
+	case '0' <= r && r <= '9':
+		return r - '0', true
+	case 'a' <= r && r <= 'f':
+		return r - 'a' + 10, true
+	case 'A' <= r && r <= 'F':
+		return r - 'A' + 10, true
+	}
+	return 0, false
+}
@@ -2,12 +2,27 @@ package es6
 
 import (
 	"io"
-	"io/ioutil"
 )
 
-// DecodeES6Script ...
+// DecodeES6Script parses a script read from r. The input is lexed as it is
+// read so the whole source is never held in memory at once.
 func DecodeES6Script(r io.Reader) (ASTNode, error) {
-	b, _ := ioutil.ReadAll(r)
-	l := Lex("", string(b), true)
-	return ParseScriptNode(l)
+	l := NewLexer("", r, true)
+	n, err := ParseScriptNode(l)
+	if readErr := l.Err(); readErr != nil {
+		return n, readErr
+	}
+	return n, err
+}
+
+// DecodeES6Module parses module code read from r as DecodeES6Script does a
+// script
+func DecodeES6Module(r io.Reader) (ASTNode, error) {
+	l := NewLexer("", r, true)
+	l.Module = true
+	n, err := ParseModuleNode(l)
+	if readErr := l.Err(); readErr != nil {
+		return n, readErr
+	}
+	return n, err
 }
@@ -0,0 +1,14 @@
+package es6
+
+import "io"
+
+// Print writes the Source of the tokens of n to w. A node parsed with the
+// Lexer's ConcreteSyntax set is written exactly as it was in the source.
+func Print(w io.Writer, n ASTNode) error {
+	for _, tok := range n.Tokens() {
+		if _, err := io.WriteString(w, tok.Source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
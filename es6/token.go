@@ -1,12 +1,51 @@
 package es6
 
-import "fmt"
+import (
+	"fmt"
+	"math/big"
+)
 
 // Token is a unit generated by the lexer whitch includes a type
 // or value
 type Token struct {
 	Type  TokenType
 	Value string
+	// Source is the token as it appears in the source, it is the Value with
+	// the delimiters of a comment. An ErrorToken's Source is the input that
+	// was skipped after the error, so the Source of every token lexed from
+	// an input joined together is the input
+	Source string
+	// Cooked is the value of a StringLiteralToken or a template token, or
+	// the name of an IdentifierNameToken, with its escape sequences and line
+	// continuations decoded
+	Cooked string
+	// Raw is the value of a template token as it appears in the source
+	// without its delimiters, with each LineTerminatorSequence as a line feed
+	Raw string
+	// CookedErr is set on a template token with an invalid escape sequence,
+	// its cooked value is then undefined. This is only allowed in tagged
+	// templates so the parser reports it for any other template
+	CookedErr error
+	// Contextual is set on an IdentifierNameToken that is a contextual
+	// keyword such as let, of or get
+	Contextual bool
+	// Number is the value of a NumericLiteralToken
+	Number float64
+	// BigInt is the value of a NumericLiteralToken with the BigInt suffix n,
+	// it is nil for any other token
+	BigInt *big.Int
+	// Pattern and Flags are the body and flags of a RegExToken
+	Pattern, Flags string
+	// NewlineBefore is set when a LineTerminator, or a comment containing
+	// one, appears between this token and the previous token that is not
+	// whitespace, a LineTerminator or a comment
+	NewlineBefore bool
+	// Goal is the goal the token was lexed with
+	Goal LexerGoal
+	// LeadingComments are the comments between this token and the previous
+	// one, except for those that are trailing comments of the previous token
+	// because a LineTerminator follows them
+	LeadingComments []Token
 	FilePosition
 }
 
@@ -37,13 +76,13 @@ func (tok Token) String() string {
 type TokenType int
 
 // not handled Type's
-// TODO missing LineTerminatorSequence
 const (
 	ErrorToken TokenType = iota
 	EOFToken
 	// Comment ::
 	MultiLineCommentToken
 	SingleLineCommentToken
+	HashbangCommentToken
 	// WhiteSpaceToken ::
 	WhiteSpaceToken
 	// LineTerminatorToken ::
@@ -51,6 +90,7 @@ const (
 	// ComomonToken ::
 	IdentifierNameToken
 	ReservedWordToken
+	PrivateNameToken
 	//   Punctuator
 	PunctuatorToken
 	RightBracePunctuatorToken
@@ -79,6 +119,8 @@ func (typ TokenType) String() string {
 		return "MultiLineComment"
 	case SingleLineCommentToken:
 		return "SingleLineComment"
+	case HashbangCommentToken:
+		return "HashbangComment"
 	case WhiteSpaceToken:
 		return "WhiteSpace"
 	case LineTerminatorToken:
@@ -87,6 +129,8 @@ func (typ TokenType) String() string {
 		return "IdentifierName"
 	case ReservedWordToken:
 		return "ReservedWord"
+	case PrivateNameToken:
+		return "PrivateName"
 	case PunctuatorToken:
 		return "Punctuator"
 	case RightBracePunctuatorToken:
@@ -112,6 +156,39 @@ func (typ TokenType) String() string {
 	}
 }
 
+// isTrivia reports whether tokens of this type carry no meaning for the
+// syntactic grammar
+func (typ TokenType) isTrivia() bool {
+	switch typ {
+	case WhiteSpaceToken, LineTerminatorToken, MultiLineCommentToken, SingleLineCommentToken, HashbangCommentToken:
+		return true
+	default:
+		return false
+	}
+}
+
+// isComment reports whether the Value of a token of this type is its Source
+// without delimiters
+func (typ TokenType) isComment() bool {
+	switch typ {
+	case MultiLineCommentToken, SingleLineCommentToken, HashbangCommentToken:
+		return true
+	default:
+		return false
+	}
+}
+
+// isGoalSensitive reports whether the input of a token of this type could
+// be lexed as a different token with a different goal
+func (typ TokenType) isGoalSensitive() bool {
+	switch typ {
+	case DivPunctuatorToken, RegExToken, RightBracePunctuatorToken, TemplateMiddleToken, TemplateTailToken, ErrorToken:
+		return true
+	default:
+		return false
+	}
+}
+
 // Equals checks if the token is equal to another token
 func (tok Token) Equals(tok2 Token) bool {
 	return tok.Type == tok2.Type && tok.Value == tok2.Value
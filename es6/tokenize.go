@@ -0,0 +1,164 @@
+package es6
+
+// Tokenize lexes src without a parser to choose the goal of each token. The
+// goal is chosen from the significant tokens before it, like the reader of
+// sweet.js and esprima does, and a stack of the open braces and template
+// substitutions tells a } that continues a template from one that closes a
+// block. The tokens include whitespace, line terminators and comments and
+// end with an EOFToken.
+func Tokenize(name, src string) []Token {
+	l := Lex(name, src, false)
+	l.CaptureWhitespaceTokens = true
+	return TokenizeLexer(l)
+}
+
+// TokenizeLexer returns the rest of the tokens of l, up to and including
+// EOF, choosing the goal of each token like Tokenize does. Whitespace, line
+// terminators and comments are only included when l captures them.
+func TokenizeLexer(l *Lexer) []Token {
+	var t tokenizer
+	// a token, counting whitespace, is about two bytes of typical source.
+	// The tokens are presized for the input that has already been read and
+	// doubled when more are lexed
+	tokens := make([]Token, 0, (len(l.input)-l.pos)/2+16)
+	for {
+		tok := l.Next(t.goal())
+		if len(tokens) == cap(tokens) {
+			tokens = append(make([]Token, 0, 2*cap(tokens)), tokens...)
+		}
+		tokens = append(tokens, tok)
+		if tok.Type == EOFToken {
+			return tokens
+		}
+		t.push(tok)
+	}
+}
+
+// braceKind is what an open brace in the tokenizer's stack belongs to
+type braceKind int
+
+const (
+	blockBrace braceKind = iota
+	objectBrace
+	substitutionBrace
+)
+
+// tokenizer tracks the tokens that Tokenize needs to choose a goal
+type tokenizer struct {
+	last        Token // the last significant token
+	started     bool  // a significant token has been seen
+	parens      []Token
+	parenOwner  Token // the token before the ( matching the last )
+	braces      []braceKind
+	closedBrace braceKind // what the last } closed
+}
+
+// goal returns the goal for the next token
+func (t *tokenizer) goal() LexerGoal {
+	regExp := t.regExpAllowed()
+	if n := len(t.braces); n > 0 && t.braces[n-1] == substitutionBrace {
+		if regExp {
+			return InputElementRegExpOrTemplateTail
+		}
+		return InputElementTemplateTail
+	}
+	if regExp {
+		return InputElementRegExp
+	}
+	return InputElementDiv
+}
+
+// regExpAllowed reports whether a / starts a RegularExpressionLiteral, that
+// is when the last token can not end an expression
+func (t *tokenizer) regExpAllowed() bool {
+	if !t.started {
+		return true
+	}
+	switch t.last.Type {
+	case PunctuatorToken:
+		switch t.last.Value {
+		case ")":
+			return t.parenOwner.Type == ReservedWordToken && isOneOf(t.parenOwner.Value, "if", "while", "for", "with")
+		case "]", "++", "--":
+			return false
+		}
+		return true
+	case RightBracePunctuatorToken:
+		return t.closedBrace == blockBrace
+	case ReservedWordToken:
+		return !isOneOf(t.last.Value, "this", "super", "null", "true", "false")
+	case DivPunctuatorToken, TemplateHeadToken, TemplateMiddleToken:
+		return true
+	default:
+		return false
+	}
+}
+
+// push records a token returned by the Lexer
+func (t *tokenizer) push(tok Token) {
+	if tok.Type.isTrivia() || tok.Type == ErrorToken {
+		return
+	}
+	switch tok.Type {
+	case PunctuatorToken:
+		switch tok.Value {
+		case "(":
+			t.parens = append(t.parens, t.last)
+		case ")":
+			t.parenOwner = Token{}
+			if n := len(t.parens); n > 0 {
+				t.parenOwner = t.parens[n-1]
+				t.parens = t.parens[:n-1]
+			}
+		case "{":
+			t.braces = append(t.braces, t.braceKind())
+		}
+	case RightBracePunctuatorToken:
+		t.closedBrace = blockBrace
+		if n := len(t.braces); n > 0 {
+			t.closedBrace = t.braces[n-1]
+			t.braces = t.braces[:n-1]
+		}
+	case TemplateHeadToken:
+		t.braces = append(t.braces, substitutionBrace)
+	case TemplateTailToken:
+		if n := len(t.braces); n > 0 {
+			t.braces = t.braces[:n-1]
+		}
+	}
+	t.last = tok
+	t.started = true
+}
+
+// braceKind returns what a { that follows the last token opens, it is an
+// object literal where an expression is expected and a block otherwise
+func (t *tokenizer) braceKind() braceKind {
+	if !t.started {
+		return blockBrace
+	}
+	switch t.last.Type {
+	case PunctuatorToken:
+		if isOneOf(t.last.Value, ")", "=>", ";", "{") {
+			return blockBrace
+		}
+		return objectBrace
+	case ReservedWordToken:
+		if isOneOf(t.last.Value, "else", "do", "try", "finally") {
+			return blockBrace
+		}
+		return objectBrace
+	case TemplateHeadToken, TemplateMiddleToken, DivPunctuatorToken:
+		return objectBrace
+	default:
+		return blockBrace
+	}
+}
+
+func isOneOf(value string, values ...string) bool {
+	for _, v := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}